@@ -0,0 +1,83 @@
+package kmeans
+
+// accumulator updates the means of a model from the sum and size of
+// each class, which are kept between updates. After the first update,
+// only data points that changed class are subtracted from their
+// previous class and added to their new class, and only the means of
+// classes that changed are updated.
+type accumulator struct {
+	cfg   Config
+	sums  Model
+	sizes []float64
+	cls   classes
+	ready bool
+}
+
+// newAccumulator returns an accumulator ready to update a model with k
+// means of a given dimension on n data points.
+func newAccumulator(cfg Config, k, dims, n int) *accumulator {
+	acc := accumulator{
+		cfg:   cfg,
+		sums:  make(Model, 0, k),
+		sizes: make([]float64, k),
+		cls:   make(classes, n),
+	}
+
+	for i := 0; i < k; i++ {
+		acc.sums = append(acc.sums, make(Point, dims))
+	}
+
+	return &acc
+}
+
+// update the model as Model.update does if the configuration is not
+// incremental. Otherwise, the sums and sizes of each class are updated
+// with the data points that changed class since the previous update
+// and only the means of those classes are updated. Empty classes are
+// repaired by the configured repair method. If training is spherical,
+// each mean is then normalized. The number of empty classes is
+// returned.
+func (acc *accumulator) update(mdl Model, cls classes, data []Point) int {
+	if !acc.cfg.Incremental {
+		return mdl.update(acc.cfg, cls, data)
+	}
+
+	changed := make([]bool, len(mdl))
+	for i := 0; i < len(data); i++ {
+		prevClass, class := acc.cls[i], cls[i]
+		switch {
+		case !acc.ready:
+			acc.sums[class].Add(data[i])
+			acc.sizes[class]++
+			changed[class] = true
+		case prevClass != class:
+			acc.sums[prevClass].Sub(data[i])
+			acc.sizes[prevClass]--
+			acc.sums[class].Add(data[i])
+			acc.sizes[class]++
+			changed[prevClass], changed[class] = true, true
+		}
+
+		acc.cls[i] = class
+	}
+
+	var empties []int
+	for i := 0; i < len(mdl); i++ {
+		switch {
+		case !changed[i] && acc.ready:
+		case acc.sizes[i] == 0:
+			empties = append(empties, i)
+		default:
+			copy(mdl[i], acc.sums[i])
+			mdl[i].ScalMult(1.0 / acc.sizes[i])
+		}
+	}
+
+	acc.ready = true
+	repairs := mdl.repair(acc.cfg, empties, cls, data)
+	if acc.cfg.Spherical {
+		Normalize(mdl...)
+	}
+
+	return repairs
+}
@@ -0,0 +1,237 @@
+package kmeans
+
+import (
+	"context"
+	"fmt"
+)
+
+// --------------------------------------------------------------------
+//    Bisecting k-means (Steinbach et al., 2000)
+// --------------------------------------------------------------------
+// 1. Start with a single cluster holding every point.
+// 2. Choose the cluster with the largest error (or the most points)
+//    that has not failed to split.
+// 3. Split the chosen cluster in two by 2-means, keeping the highest
+//    scoring of several trials. If either child is empty,
+//    seed the means from two distinct points and train again.
+// 4. Return when there are k clusters, otherwise go to step 2.
+// --------------------------------------------------------------------
+//  * A comparison of document clustering techniques.
+//    https://conservancy.umn.edu/handle/11299/215421
+// --------------------------------------------------------------------
+
+// SplitNode is a cluster in a split tree. The error is the inertia of
+// the cluster. That is, the sum of the cost by the configured metric of
+// the distance from each data point in the cluster to its mean.
+type SplitNode struct {
+	Mean     Point
+	Size     int
+	Err      float64
+	Parent   int
+	Children []int
+	Class    int
+}
+
+// SplitTree records each cluster split by bisecting k-means. The root
+// node, at index zero, holds the full data set and has no parent (-1).
+// Each split node has two children, and each leaf node is a cluster of
+// the returned model with the class given. Split nodes have no class
+// (-1).
+type SplitTree []SplitNode
+
+// Bisect returns a model trained by bisecting k-means and the tree of
+// clusters split. Each split is trained by 2-means with the configured
+// options, keeping the highest scoring of the configured number of
+// bisect trials. An error is returned if the model cannot be trained,
+// or if fewer than k clusters can be split from the data.
+func Bisect(k int, data []Point, opts ...Option) (Model, SplitTree, error) {
+	return BisectContext(context.Background(), k, data, opts...)
+}
+
+// BisectContext returns a model and split tree as Bisect does, but
+// stops splitting once the context is canceled. If canceled, the model
+// and tree split so far are returned with the context's error.
+func BisectContext(ctx context.Context, k int, data []Point, opts ...Option) (Model, SplitTree, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := validate(k, data); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		root   = Model{make(Point, len(data[0]))}
+		nodes  = make([]int, len(data))
+		leaves = []int{0}
+		failed = make(map[int]bool)
+	)
+
+	root.update(cfg, make(classes, len(data)), data)
+	tree := SplitTree{{Mean: root[0], Size: len(data), Err: -root.score(cfg.Metric, data), Parent: -1}}
+	for len(leaves) < k {
+		if err := ctx.Err(); err != nil {
+			return tree.model(leaves), tree, err
+		}
+
+		leaf := tree.next(cfg, leaves, failed)
+		if leaf < 0 {
+			return tree.model(leaves), tree, fmt.Errorf("%w: %d clusters split of %d", ErrDataSize, len(leaves), k)
+		}
+
+		cluster := make([]Point, 0, tree[leaf].Size)
+		for i := 0; i < len(data); i++ {
+			if nodes[i] == leaf {
+				cluster = append(cluster, data[i])
+			}
+		}
+
+		mdl, err := bisect(ctx, cfg, cluster)
+		if err != nil {
+			return tree.model(leaves), tree, err
+		}
+
+		var (
+			cls   = make(classes, len(cluster))
+			sizes = make([]int, 2)
+			errs  = make([]float64, 2)
+		)
+
+		for i := 0; i < len(cluster); i++ {
+			var dist float64
+			cls[i], dist = mdl.classDist(cfg.Metric, cluster[i])
+			sizes[cls[i]]++
+			errs[cls[i]] += cfg.Metric.Cost(dist)
+		}
+
+		if sizes[0] == 0 || sizes[1] == 0 {
+			failed[leaf] = true
+			continue
+		}
+
+		children := []int{len(tree), len(tree) + 1}
+		for i := 0; i < len(children); i++ {
+			tree = append(tree, SplitNode{Mean: mdl[i], Size: sizes[i], Err: errs[i], Parent: leaf})
+		}
+
+		tree[leaf].Children = children
+		for i, j := 0, 0; i < len(data); i++ {
+			if nodes[i] == leaf {
+				nodes[i] = children[cls[j]]
+				j++
+			}
+		}
+
+		for i := 0; i < len(leaves); i++ {
+			if leaves[i] == leaf {
+				leaves = append(leaves[:i], leaves[i+1:]...)
+				break
+			}
+		}
+
+		leaves = append(leaves, children...)
+	}
+
+	return tree.model(leaves), tree, nil
+}
+
+// bisect returns the two means splitting a cluster by 2-means. If
+// either child is empty, as when every data point ties to the first
+// mean, the means are instead seeded from the first data point and the
+// data point farthest from it and trained again.
+func bisect(ctx context.Context, cfg Config, cluster []Point) (Model, error) {
+	cfg.TrainRounds = cfg.BisectTrials
+	mdl, _, err := newModel(ctx, 2, cluster, cfg, false)
+	if err != nil {
+		return mdl, err
+	}
+
+	sizes := make([]int, 2)
+	for i := 0; i < len(cluster); i++ {
+		class, _ := mdl.classDist(cfg.Metric, cluster[i])
+		sizes[class]++
+	}
+
+	if sizes[0] != 0 && sizes[1] != 0 {
+		return mdl, nil
+	}
+
+	var (
+		far     int
+		maxDist float64
+	)
+
+	for i := 1; i < len(cluster); i++ {
+		if dist := cfg.Metric.Dist(cluster[0], cluster[i]); maxDist < dist || far == 0 && !cluster[0].Equals(cluster[i]) {
+			far, maxDist = i, dist
+		}
+	}
+
+	if far == 0 {
+		// Fewer than two distinct data points
+		return mdl, nil
+	}
+
+	var (
+		meanDists = newTriMatrix(2)
+		cls       = make(classes, len(cluster))
+	)
+
+	mdl = Model{cluster[0].Copy(), cluster[far].Copy()}
+	meanDists.update(cfg.Metric, mdl)
+	if mdl.train(newMonitor(ctx, cfg, 0, mdl, nil), meanDists, cls, cluster) == Canceled {
+		return mdl, ctx.Err()
+	}
+
+	return mdl, nil
+}
+
+// next returns the leaf to split next by the configured bisect method,
+// skipping leaves that failed to split. If no leaf can be split, -1 is
+// returned.
+func (tree SplitTree) next(cfg Config, leaves []int, failed map[int]bool) int {
+	next := -1
+	for _, leaf := range leaves {
+		if failed[leaf] || tree[leaf].Size < 2 {
+			continue
+		}
+
+		if next < 0 {
+			next = leaf
+			continue
+		}
+
+		switch cfg.BisectMthd {
+		case BisectMaxErr:
+			if tree[next].Err < tree[leaf].Err {
+				next = leaf
+			}
+		case BisectLargest:
+			if tree[next].Size < tree[leaf].Size {
+				next = leaf
+			}
+		default:
+			panic(ErrBisectMthd)
+		}
+	}
+
+	return next
+}
+
+// model returns the model of the means of the given leaves, setting the
+// class of each node. Leaves are classified in the given order and
+// every other node has no class.
+func (tree SplitTree) model(leaves []int) Model {
+	for i := 0; i < len(tree); i++ {
+		tree[i].Class = -1
+	}
+
+	mdl := make(Model, 0, len(leaves))
+	for class, leaf := range leaves {
+		tree[leaf].Class = class
+		mdl = append(mdl, tree[leaf].Mean.Copy())
+	}
+
+	return mdl
+}
@@ -0,0 +1,31 @@
+package kmeans
+
+// BisectMethod defines which cluster bisecting k-means splits next.
+type BisectMethod uint
+
+const (
+	// BisectMaxErr indicates the cluster with the largest error will be
+	// split next.
+	BisectMaxErr BisectMethod = 1 + iota
+
+	// BisectLargest indicates the cluster with the most data points
+	// will be split next.
+	BisectLargest
+)
+
+// String describes a bisect method.
+func (mthd BisectMethod) String() string {
+	switch mthd {
+	case BisectMaxErr:
+		return "max-err"
+	case BisectLargest:
+		return "largest"
+	default:
+		return "invalid"
+	}
+}
+
+// valid determines if a bisect method is defined.
+func (mthd BisectMethod) valid() bool {
+	return BisectMaxErr <= mthd && mthd <= BisectLargest
+}
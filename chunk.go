@@ -0,0 +1,93 @@
+package kmeans
+
+import "sync"
+
+// chunkSize is the least number of data points in each chunk of a
+// step shared among workers, and maxChunks is the greatest number of
+// chunks. Chunks depend only on the number of data points, not the
+// number of workers, so results reduced in chunk order are
+// deterministic. Capping the number of chunks bounds the memory of
+// partial results regardless of the size of the data set.
+const (
+	chunkSize = 1 << 12
+	maxChunks = 64
+)
+
+// chunks returns the number of chunks n data points are divided into.
+func chunks(n int) int {
+	return (n + chunkLen(n) - 1) / chunkLen(n)
+}
+
+// chunkLen returns the number of data points in each chunk of n data
+// points. The last chunk may hold fewer.
+func chunkLen(n int) int {
+	if size := (n + maxChunks - 1) / maxChunks; chunkSize < size {
+		return size
+	}
+
+	return chunkSize
+}
+
+// forChunks calls f on each chunk of n data points, given the index of
+// the chunk and the range [lo, hi) of data points in it. The chunks
+// are shared among up to the given number of goroutines.
+func forChunks(workers, n int, f func(chunk, lo, hi int)) {
+	var (
+		m    = chunks(n)
+		size = chunkLen(n)
+		span = func(chunk int) (int, int) {
+			lo, hi := chunk*size, (chunk+1)*size
+			if n < hi {
+				hi = n
+			}
+
+			return lo, hi
+		}
+	)
+
+	if workers <= 1 || m <= 1 {
+		for chunk := 0; chunk < m; chunk++ {
+			lo, hi := span(chunk)
+			f(chunk, lo, hi)
+		}
+
+		return
+	}
+
+	var (
+		jobs = make(chan int)
+		wg   sync.WaitGroup
+	)
+
+	for i := 0; i < workers && i < m; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				lo, hi := span(chunk)
+				f(chunk, lo, hi)
+			}
+		}()
+	}
+
+	for chunk := 0; chunk < m; chunk++ {
+		jobs <- chunk
+	}
+
+	close(jobs)
+	wg.Wait()
+}
+
+// countChunks calls f on each chunk of n data points as forChunks does
+// and returns the sum of the counts returned for each chunk.
+func countChunks(workers, n int, f func(lo, hi int) int) int {
+	counts := make([]int, chunks(n))
+	forChunks(workers, n, func(chunk, lo, hi int) { counts[chunk] = f(lo, hi) })
+
+	var total int
+	for i := 0; i < len(counts); i++ {
+		total += counts[i]
+	}
+
+	return total
+}
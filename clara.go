@@ -0,0 +1,162 @@
+package kmeans
+
+import (
+	"context"
+	"math"
+)
+
+// --------------------------------------------------------------------
+//    CLARA (Kaufman and Rousseeuw, 1990)
+// --------------------------------------------------------------------
+// 1. Draw a sample of the data, including the best medoids so far.
+// 2. Build and train medoids on the sample by FasterPAM, caching only
+//    the distances between pairs of sampled points.
+// 3. Compute the total distance from every data point to its nearest
+//    medoid, keeping the medoids if they are the best so far.
+// 4. Return after a number of samples, otherwise go to step 1.
+// --------------------------------------------------------------------
+
+// --------------------------------------------------------------------
+//    CLARANS (Ng and Han, 2002)
+// --------------------------------------------------------------------
+// 1. Initialize k medoids as data points.
+// 2. Choose a random non-medoid x and compute the change in total
+//    distance from swapping x with every medoid at once, as FasterPAM
+//    does.
+// 3. If the least change reduces the total distance, apply the swap.
+// 4. Return once a number of consecutive neighbors fail to reduce the
+//    total distance, otherwise go to step 2.
+// --------------------------------------------------------------------
+//  * CLARANS: a method for clustering objects for spatial data mining.
+//    https://doi.org/10.1109/TKDE.2002.1033770
+// --------------------------------------------------------------------
+
+// trainCLARA updates the medoids by training on the configured number
+// of samples of a data set, keeping the medoids with the least cost on
+// the full data set. The cost is returned.
+func (med Medoids) trainCLARA(ctx context.Context, cfg Config, data []Point) float64 {
+	var (
+		k    = len(med)
+		size = cfg.SampleSize
+	)
+
+	switch {
+	case size == 0:
+		size = 40 + 2*k
+	case size < k:
+		size = k
+	}
+
+	if len(data) < size {
+		size = len(data)
+	}
+
+	var (
+		sample      = make([]int, 0, size)
+		samplePts   = make([]Point, size)
+		sampleDists = newTriMatrix(size)
+		sampleMed   = make(Medoids, k)
+		near        = make([]int, len(data))
+		nearDist    = make([]float64, len(data))
+		dist        = func(i, j int) float64 { return cfg.Metric.Dist(data[i], data[j]) }
+		minCost     = math.Inf(1)
+	)
+
+	for s := 0; s < cfg.Samples; s++ {
+		if ctx.Err() != nil && s != 0 {
+			break
+		}
+
+		// Later samples include the best medoids so far
+		sample = sample[:0]
+		if s != 0 {
+			sample = append(sample, med...)
+		}
+
+		perm := cfg.Rand.Perm(len(data))
+		for i := 0; i < len(perm) && len(sample) < size; i++ {
+			if s == 0 || !med.contains(perm[i]) {
+				sample = append(sample, perm[i])
+			}
+		}
+
+		for i := 0; i < len(sample); i++ {
+			samplePts[i] = data[sample[i]]
+		}
+
+		sampleDists.update(cfg.Metric, samplePts)
+		sampleMed.build(size, sampleDists.dist)
+		sampleMed.trainSwap(ctx, cfg, size, sampleDists.dist, true)
+		for c := 0; c < k; c++ {
+			sampleMed[c] = sample[sampleMed[c]]
+		}
+
+		if cost := sampleMed.assign(len(data), dist, near, nearDist, nil); cost < minCost {
+			copy(med, sampleMed)
+			minCost = cost
+		}
+	}
+
+	return minCost
+}
+
+// trainCLARANS updates the medoids on n data points, given the
+// distance between each pair, by swapping a medoid with a random
+// non-medoid whenever doing so reduces the cost. Training stops once
+// the configured number of neighbors are tried in a row without a
+// swap. The cost is returned.
+func (med Medoids) trainCLARANS(ctx context.Context, cfg Config, n int, dist func(i, j int) float64) float64 {
+	var (
+		k         = len(med)
+		neighbors = cfg.Neighbors
+	)
+
+	if neighbors == 0 {
+		neighbors = int(math.Max(250, 0.0125*float64(k*(n-k))))
+	}
+
+	var (
+		near       = make([]int, n)
+		nearDist   = make([]float64, n)
+		secondDist = make([]float64, n)
+		loss       = make([]float64, k)
+		deltas     = make([]float64, k)
+		cost       = med.assign(n, dist, near, nearDist, secondDist)
+		swaps      int
+	)
+
+	removalLoss(loss, near, nearDist, secondDist)
+	for tries := 0; tries < neighbors; tries++ {
+		i := cfg.Rand.Intn(n)
+		if med.contains(i) {
+			continue
+		}
+
+		delta, c := swapDelta(i, n, dist, loss, deltas, near, nearDist, secondDist)
+		if 0 <= delta {
+			continue
+		}
+
+		med[c] = i
+		cost = med.assign(n, dist, near, nearDist, secondDist)
+		removalLoss(loss, near, nearDist, secondDist)
+		if swaps++; 0 < cfg.MaxIters && cfg.MaxIters <= swaps || ctx.Err() != nil {
+			break
+		}
+
+		tries = -1
+	}
+
+	return cost
+}
+
+// contains determines if the ith data point is a medoid.
+func (med Medoids) contains(i int) bool {
+	for c := 0; c < len(med); c++ {
+		if med[c] == i {
+			return true
+		}
+	}
+
+	return false
+}
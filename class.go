@@ -6,15 +6,19 @@ package kmeans
 // classes i corresponding to one of the k means.
 type classes []int
 
-// update updates each classification and determines if any changes
-// were made.
-func (cls classes) update(mdl Model, meanDists triMatrix, data []Point) bool {
-	var changed bool
-	for i := 0; i < len(data); i++ {
-		prevClass := cls[i]
-		cls[i], _ = mdl.classDistMem(data[i], meanDists)
-		changed = changed || cls[i] != prevClass
-	}
+// update updates each classification by the configured metric and
+// returns the number of changes made. The data is shared among the
+// configured number of step workers.
+func (cls classes) update(cfg Config, mdl Model, meanDists triMatrix, data []Point) int {
+	return countChunks(cfg.StepWorkers, len(data), func(lo, hi int) int {
+		var changes int
+		for i := lo; i < hi; i++ {
+			prevClass := cls[i]
+			if cls[i], _ = mdl.classDistMem(cfg.Metric, data[i], meanDists); cls[i] != prevClass {
+				changes++
+			}
+		}
 
-	return changed
+		return changes
+	})
 }
@@ -1,23 +1,152 @@
 package kmeans
 
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+)
+
 // Config exposes configuration options to the caller.
 type Config struct {
-	TrainRounds int
-	Mthd        InitMethod
+	TrainRounds  int
+	Mthd         InitMethod
+	Trials       int
+	InitRounds   int
+	Oversampling float64
+	TrainMthd    TrainMethod
+	BatchSize    int
+	BatchIters   int
+	BatchTol     float64
+	MaxIters     int
+	ShiftTol     float64
+	InertiaTol   float64
+	Rand         *rand.Rand
+	Observer     Observer
+	Workers      int
+	StepWorkers  int
+	Incremental  bool
+	RepairMthd   RepairMethod
+	Metric       Metric
+	Spherical    bool
+	Medians      bool
+	MedoidMthd   MedoidMethod
+	Samples      int
+	SampleSize   int
+	Neighbors    int
+	BisectMthd   BisectMethod
+	BisectTrials int
+	Significance float64
+	References   int
+	PCABox       bool
 }
 
 // NewConfig returns the default configuration updated with any
 // options.
 func NewConfig(opts ...Option) Config {
 	cfg := Config{
-		TrainRounds: 1,
-		Mthd:        Random,
+		TrainRounds:  1,
+		Mthd:         Random,
+		InitRounds:   5,
+		TrainMthd:    Lloyd,
+		BatchSize:    100,
+		BatchIters:   100,
+		Rand:         rand.New(rand.NewSource(rand.Int63())),
+		Workers:      runtime.GOMAXPROCS(0),
+		StepWorkers:  1,
+		RepairMthd:   RepairRandom,
+		Metric:       Euclidean{},
+		MedoidMthd:   FasterPAM,
+		Samples:      5,
+		BisectMthd:   BisectMaxErr,
+		BisectTrials: 5,
+		Significance: 0.0001,
+		References:   10,
 	}
 
 	cfg.update(opts...)
 	return cfg
 }
 
+// Validate determines if each configuration value is valid.
+func (cfg Config) Validate() error {
+	switch {
+	case cfg.TrainRounds < 1:
+		return fmt.Errorf("%w: %d training rounds", ErrOption, cfg.TrainRounds)
+	case !cfg.Mthd.valid():
+		return fmt.Errorf("%w: %d", ErrInitMthd, cfg.Mthd)
+	case cfg.InitRounds < 0:
+		return fmt.Errorf("%w: %d initialization rounds", ErrOption, cfg.InitRounds)
+	case math.IsNaN(cfg.Oversampling) || math.IsInf(cfg.Oversampling, 0):
+		return fmt.Errorf("%w: oversampling factor %f", ErrOption, cfg.Oversampling)
+	case !cfg.TrainMthd.valid():
+		return fmt.Errorf("%w: %d", ErrTrainMthd, cfg.TrainMthd)
+	case cfg.BatchSize < 1:
+		return fmt.Errorf("%w: batch size %d", ErrOption, cfg.BatchSize)
+	case cfg.BatchIters < 1:
+		return fmt.Errorf("%w: %d batches", ErrOption, cfg.BatchIters)
+	case math.IsNaN(cfg.BatchTol):
+		return fmt.Errorf("%w: batch tolerance %f", ErrOption, cfg.BatchTol)
+	case cfg.MaxIters < 0:
+		return fmt.Errorf("%w: %d maximum iterations", ErrOption, cfg.MaxIters)
+	case math.IsNaN(cfg.ShiftTol):
+		return fmt.Errorf("%w: shift tolerance %f", ErrOption, cfg.ShiftTol)
+	case math.IsNaN(cfg.InertiaTol):
+		return fmt.Errorf("%w: inertia tolerance %f", ErrOption, cfg.InertiaTol)
+	case cfg.Workers < 1:
+		return fmt.Errorf("%w: %d workers", ErrOption, cfg.Workers)
+	case cfg.StepWorkers < 1:
+		return fmt.Errorf("%w: %d step workers", ErrOption, cfg.StepWorkers)
+	case !cfg.RepairMthd.valid():
+		return fmt.Errorf("%w: %d", ErrRepairMthd, cfg.RepairMthd)
+	case !cfg.MedoidMthd.valid():
+		return fmt.Errorf("%w: %d", ErrMedoidMthd, cfg.MedoidMthd)
+	case cfg.Samples < 1:
+		return fmt.Errorf("%w: %d samples", ErrOption, cfg.Samples)
+	case cfg.SampleSize < 0:
+		return fmt.Errorf("%w: sample size %d", ErrOption, cfg.SampleSize)
+	case cfg.Neighbors < 0:
+		return fmt.Errorf("%w: %d neighbors", ErrOption, cfg.Neighbors)
+	case !cfg.BisectMthd.valid():
+		return fmt.Errorf("%w: %d", ErrBisectMthd, cfg.BisectMthd)
+	case cfg.BisectTrials < 1:
+		return fmt.Errorf("%w: %d bisect trials", ErrOption, cfg.BisectTrials)
+	case !(0 < cfg.Significance && cfg.Significance < 1):
+		return fmt.Errorf("%w: significance level %f", ErrOption, cfg.Significance)
+	case cfg.References < 1:
+		return fmt.Errorf("%w: %d reference data sets", ErrOption, cfg.References)
+	case cfg.Metric == nil:
+		return fmt.Errorf("%w: no metric", ErrOption)
+	case !cfg.Metric.Triangle() && (cfg.TrainMthd == Elkan || cfg.TrainMthd == Hamerly):
+		return fmt.Errorf("%w: %s training requires a metric satisfying the triangle inequality", ErrOption, cfg.TrainMthd)
+	case cfg.Rand == nil:
+		return fmt.Errorf("%w: no random source", ErrOption)
+	}
+
+	if m, ok := cfg.Metric.(Minkowski); ok && !(0 < m.P) {
+		return fmt.Errorf("%w: minkowski order %f", ErrOption, m.P)
+	}
+
+	if _, ok := cfg.Metric.(Cosine); cfg.Spherical && !ok {
+		return fmt.Errorf("%w: spherical training requires the cosine metric", ErrOption)
+	}
+
+	if cfg.Medians {
+		if _, ok := cfg.Metric.(Manhattan); !ok {
+			return fmt.Errorf("%w: k-medians requires the manhattan metric", ErrOption)
+		}
+
+		switch {
+		case cfg.Incremental:
+			return fmt.Errorf("%w: k-medians does not support incremental updates", ErrOption)
+		case cfg.TrainMthd == MiniBatch:
+			return fmt.Errorf("%w: k-medians does not support %s training", ErrOption, cfg.TrainMthd)
+		}
+	}
+
+	return nil
+}
+
 // update a configuration.
 func (cfg *Config) update(opts ...Option) {
 	for i := 0; i < len(opts); i++ {
@@ -0,0 +1,117 @@
+package kmeans
+
+import (
+	"math"
+	"math/rand"
+)
+
+// --------------------------------------------------------------------
+//    k-Means++ by D² sampling (Arthur and Vassilvitskii, 2007)
+// --------------------------------------------------------------------
+// 1. Initialize the first mean as a random data point.
+// 2. Initialize each remaining mean with a random data point, where
+//    each data point is chosen with probability proportional to its
+//    squared distance D² from the nearest initialized mean.
+// 3. Continue from step 2 of the naive algorithm.
+// --------------------------------------------------------------------
+// The greedy variant samples several candidates in step 2 and keeps
+// the candidate that most reduces the sum of squared distances from
+// each data point to its nearest initialized mean.
+// --------------------------------------------------------------------
+//  * k-means++: the advantages of careful seeding.
+//    https://theory.stanford.edu/~sergei/papers/kMeansPP-soda.pdf
+// --------------------------------------------------------------------
+
+// initD2 initializes a model by D² sampling, trying the given number
+// of candidates for each mean and measuring distances by the given
+// metric. Each data point may be weighted, in which case its squared
+// distance is scaled by its weight. If no weights are provided, each
+// data point has weight one. The mean distances are updated.
+func (mdl Model) initD2(rnd *rand.Rand, metric Metric, trials int, meanDists triMatrix, data []Point, weights []float64) {
+	if weights == nil {
+		weights = make([]float64, len(data))
+		for j := 0; j < len(weights); j++ {
+			weights[j] = 1
+		}
+	}
+
+	var (
+		minSqDists  = make([]float64, len(data))
+		candSqDists = make([]float64, len(data))
+		first       = data[sampleD2(rnd, weights, sum(weights))]
+		pot         float64
+	)
+
+	copy(mdl[0], first)
+	for j := 0; j < len(data); j++ {
+		minSqDists[j] = weights[j] * sqDist(metric, first, data[j])
+		pot += minSqDists[j]
+	}
+
+	for i := 1; i < len(mdl); i++ {
+		var (
+			bestCand = -1
+			bestPot  = math.MaxFloat64
+		)
+
+		for t := 0; t < trials; t++ {
+			cand := sampleD2(rnd, minSqDists, pot)
+
+			var candPot float64
+			for j := 0; j < len(data); j++ {
+				candSqDists[j] = math.Min(minSqDists[j], weights[j]*sqDist(metric, data[cand], data[j]))
+				candPot += candSqDists[j]
+			}
+
+			if candPot < bestPot {
+				bestCand = cand
+				bestPot = candPot
+			}
+		}
+
+		copy(mdl[i], data[bestCand])
+		pot = 0
+		for j := 0; j < len(data); j++ {
+			minSqDists[j] = math.Min(minSqDists[j], weights[j]*sqDist(metric, data[bestCand], data[j]))
+			pot += minSqDists[j]
+		}
+	}
+
+	meanDists.update(metric, mdl)
+}
+
+// sampleD2 returns the index of a random data point chosen with
+// probability proportional to its (weighted) squared distance from the
+// nearest mean. If every squared distance is zero, the index is chosen
+// uniformly.
+func sampleD2(rnd *rand.Rand, minSqDists []float64, pot float64) int {
+	if pot <= 0 {
+		return rnd.Intn(len(minSqDists))
+	}
+
+	r := rnd.Float64() * pot
+	for j := 0; j < len(minSqDists); j++ {
+		if r -= minSqDists[j]; r < 0 {
+			return j
+		}
+	}
+
+	return len(minSqDists) - 1
+}
+
+// greedyTrials returns the number of candidates sampled for each mean
+// by the greedy variant of D² sampling. This is 2+ln(k), as suggested
+// by Arthur and Vassilvitskii.
+func greedyTrials(k int) int {
+	return 2 + int(math.Log(float64(k)))
+}
+
+// sum returns the sum of a list of values.
+func sum(values []float64) float64 {
+	var s float64
+	for i := 0; i < len(values); i++ {
+		s += values[i]
+	}
+
+	return s
+}
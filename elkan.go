@@ -0,0 +1,144 @@
+package kmeans
+
+import "math"
+
+// --------------------------------------------------------------------
+//    Elkan's algorithm
+// --------------------------------------------------------------------
+// 1. Assign each point to its nearest mean, recording an upper bound
+//    u(x) on the distance from x to its mean and a lower bound
+//    l(x, c) on the distance from x to each mean c.
+// 2. Update the means and measure how far each mean drifted.
+// 3. Loosen the bounds by the drift: u(x) grows by the drift of its
+//    own mean and each l(x, c) shrinks by the drift of mean c.
+// 4. Skip x entirely if u(x) is at most half the distance from its
+//    mean to the nearest other mean. Otherwise, skip each mean c with
+//    u(x) <= l(x, c) or u(x) <= d(c(x), c) / 2, tightening u(x) once
+//    before computing d(x, c) for any remaining mean.
+// 5. Return when no reassignments are made, otherwise go to step 2.
+// --------------------------------------------------------------------
+
+// trainElkan updates the means using the given data set and mean
+// distance lookup table, stopping as the monitor determines. The
+// resulting means are the same as those produced by trainLloyd.
+func (mdl Model) trainElkan(mon *monitor, meanDists triMatrix, cls classes, data []Point) StopReason {
+	var (
+		k            = len(mdl)
+		upper        = make([]float64, len(data))
+		lower        = make([]float64, len(data)*k)
+		halfMinDists = make([]float64, k)
+		drifts       = make([]float64, k)
+		acc          = newAccumulator(mon.cfg, k, len(mdl[0]), len(data))
+	)
+
+	// Each data point's bounds are independent of the others, so the
+	// data is shared among the configured number of step workers
+	changes := countChunks(mon.cfg.StepWorkers, len(data), func(lo, hi int) int {
+		var changes int
+		for i := lo; i < hi; i++ {
+			var (
+				lwr     = lower[i*k : (i+1)*k]
+				class   int
+				minDist = mon.cfg.Metric.Dist(mdl[class], data[i])
+			)
+
+			lwr[class] = minDist
+			for j := 1; j < k; j++ {
+				if meanDists.dist(class, j)/2.0 < minDist {
+					dist := mon.cfg.Metric.Dist(mdl[j], data[i])
+					lwr[j] = dist
+					if dist < minDist {
+						class = j
+						minDist = dist
+					}
+				}
+			}
+
+			upper[i] = minDist
+			if class != cls[i] {
+				cls[i] = class
+				changes++
+			}
+		}
+
+		return changes
+	})
+
+	for mon.assigned(mdl, changes); 0 < changes; mon.assigned(mdl, changes) {
+		mon.before(mdl)
+		repairs := acc.update(mdl, cls, data)
+		meanDists.update(mon.cfg.Metric, mdl)
+		if rsn := mon.after(mdl, data, repairs); rsn != 0 {
+			return rsn
+		}
+
+		for j := 0; j < k; j++ {
+			drifts[j] = mon.cfg.Metric.Dist(mon.prevMdl[j], mdl[j])
+			halfMinDists[j] = halfMinDist(meanDists, j, k)
+		}
+
+		changes = countChunks(mon.cfg.StepWorkers, len(data), func(lo, hi int) int {
+			var changes int
+			for i := lo; i < hi; i++ {
+				class := cls[i]
+				upper[i] += drifts[class]
+
+				lwr := lower[i*k : (i+1)*k]
+				for j := 0; j < k; j++ {
+					if lwr[j] -= drifts[j]; lwr[j] < 0 {
+						lwr[j] = 0
+					}
+				}
+
+				if upper[i] <= halfMinDists[class] {
+					continue
+				}
+
+				var tight bool
+				for j := 0; j < k; j++ {
+					if j == class || upper[i] <= lwr[j] || upper[i] <= meanDists.dist(class, j)/2.0 {
+						continue
+					}
+
+					if !tight {
+						upper[i] = mon.cfg.Metric.Dist(mdl[class], data[i])
+						lwr[class] = upper[i]
+						tight = true
+						if upper[i] <= lwr[j] || upper[i] <= meanDists.dist(class, j)/2.0 {
+							continue
+						}
+					}
+
+					dist := mon.cfg.Metric.Dist(mdl[j], data[i])
+					lwr[j] = dist
+					if dist < upper[i] {
+						class = j
+						upper[i] = dist
+					}
+				}
+
+				if class != cls[i] {
+					cls[i] = class
+					changes++
+				}
+			}
+
+			return changes
+		})
+	}
+
+	return Converged
+}
+
+// halfMinDist returns half the distance from the ith mean to its
+// nearest other mean.
+func halfMinDist(meanDists triMatrix, i, k int) float64 {
+	minDist := math.MaxFloat64
+	for j := 0; j < k; j++ {
+		if j != i {
+			minDist = math.Min(minDist, meanDists.dist(i, j))
+		}
+	}
+
+	return minDist / 2.0
+}
@@ -1,12 +1,39 @@
 package kmeans
 
+import "errors"
+
 var (
-	// errDataSize reports not enough data was provided.
-	errDataSize = "insufficient data"
+	// ErrBisectMthd reports an invalid bisect method was provided.
+	ErrBisectMthd = errors.New("invalid bisect method")
+
+	// ErrDataSize reports not enough data was provided.
+	ErrDataSize = errors.New("insufficient data")
+
+	// ErrEmptyCluster reports a cluster became empty during training.
+	ErrEmptyCluster = errors.New("empty cluster")
+
+	// ErrDims reports one or more items are incompatible, notably in
+	// slices.
+	ErrDims = errors.New("unequal dimensions")
+
+	// ErrInitMthd reports an invalid intialization method was provided.
+	ErrInitMthd = errors.New("invalid initialization method")
+
+	// ErrK reports the number of clusters k is not positive.
+	ErrK = errors.New("invalid number of clusters")
+
+	// ErrMedoidMthd reports an invalid medoid method was provided.
+	ErrMedoidMthd = errors.New("invalid medoid method")
+
+	// ErrNonFinite reports a value is NaN or infinite.
+	ErrNonFinite = errors.New("non-finite value")
+
+	// ErrOption reports an invalid option value was provided.
+	ErrOption = errors.New("invalid option")
 
-	// errDims reports one or more items are incompatible, notably in slices.
-	errDims = "unequal dimensions"
+	// ErrRepairMthd reports an invalid repair method was provided.
+	ErrRepairMthd = errors.New("invalid repair method")
 
-	// errInitMthd reports an invalid intialization method was provided.
-	errInitMthd = "invalid initialization method"
+	// ErrTrainMthd reports an invalid training method was provided.
+	ErrTrainMthd = errors.New("invalid training method")
 )
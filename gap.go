@@ -0,0 +1,282 @@
+package kmeans
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// --------------------------------------------------------------------
+//    Gap statistic (Tibshirani, Walther, and Hastie, 2001)
+// --------------------------------------------------------------------
+// 1. For each k, train a model on the data and compute the log of the
+//    within-cluster dispersion W(k). That is, the sum of squared
+//    distances from each point to its mean.
+// 2. Sample B reference data sets uniformly over the bounding box of
+//    the data, or the box aligned with its principal components, and
+//    compute the log of the dispersion of a model trained on each.
+// 3. The gap is the mean log dispersion of the reference data sets
+//    minus that of the data, and its standard error is the standard
+//    deviation of the reference log dispersions times sqrt(1+1/B).
+// 4. Return the least k with gap(k) >= gap(k+1) - s(k+1).
+// --------------------------------------------------------------------
+//  * Estimating the number of clusters in a data set via the gap
+//    statistic.
+//    https://doi.org/10.1111/1467-9868.00293
+// --------------------------------------------------------------------
+
+// GapStep describes the gap statistic for k means. The dispersion is
+// the sum of squared distances from each data point to its mean, and
+// the reference dispersion is averaged over each reference data set.
+type GapStep struct {
+	K          int
+	LogDisp    float64
+	RefLogDisp float64
+	Gap        float64
+	SE         float64
+}
+
+// GapReport describes the gap statistic for each k in a range and the
+// k recommended by the one standard error rule.
+type GapReport struct {
+	Steps []GapStep
+	K     int
+}
+
+// Gap returns the gap statistic for each k from kMin to kMax, comparing
+// models trained with the given options on the data and on the
+// configured number of reference data sets. An error is returned if
+// the options or data are invalid, if a model cannot be trained, or if
+// there are no more distinct data points than kMax, as the dispersion
+// of the data would be zero.
+func Gap(kMin, kMax int, data []Point, opts ...Option) (GapReport, error) {
+	return GapContext(context.Background(), kMin, kMax, data, opts...)
+}
+
+// GapContext returns a report as Gap does, but stops training once the
+// context is canceled, returning the context's error.
+func GapContext(ctx context.Context, kMin, kMax int, data []Point, opts ...Option) (GapReport, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.Validate(); err != nil {
+		return GapReport{}, err
+	}
+
+	if kMax < kMin {
+		return GapReport{}, fmt.Errorf("%w: %d to %d", ErrK, kMin, kMax)
+	}
+
+	if err := validate(kMin, data); err != nil {
+		return GapReport{}, err
+	}
+
+	if err := validate(kMax, data); err != nil {
+		return GapReport{}, err
+	}
+
+	if n := distinct(data); n <= kMax {
+		return GapReport{}, fmt.Errorf("%w: %d distinct points for at most %d clusters", ErrDataSize, n, kMax)
+	}
+
+	var (
+		sample = newBoxSampler(cfg.PCABox, data)
+		refs   = make([][]Point, cfg.References)
+		steps  = make([]GapStep, 0, kMax-kMin+1)
+		newMdl = func(k int, data []Point) (Model, error) {
+			// Each model draws from its own source seeded by the
+			// configured source
+			mdlCfg := cfg
+			mdlCfg.Rand = rand.New(rand.NewSource(cfg.Rand.Int63()))
+			mdl, _, err := newModel(ctx, k, data, mdlCfg, false)
+			return mdl, err
+		}
+	)
+
+	for b := 0; b < len(refs); b++ {
+		refs[b] = sample(cfg.Rand, len(data))
+	}
+
+	for k := kMin; k <= kMax; k++ {
+		mdl, err := newMdl(k, data)
+		if err != nil {
+			return GapReport{}, err
+		}
+
+		var (
+			step        = GapStep{K: k, LogDisp: logDisp(mdl, data)}
+			refLogDisps = make([]float64, len(refs))
+		)
+
+		for b := 0; b < len(refs); b++ {
+			refMdl, err := newMdl(k, refs[b])
+			if err != nil {
+				return GapReport{}, err
+			}
+
+			refLogDisps[b] = logDisp(refMdl, refs[b])
+			step.RefLogDisp += refLogDisps[b]
+		}
+
+		step.RefLogDisp /= float64(len(refs))
+		step.Gap = step.RefLogDisp - step.LogDisp
+
+		var variance float64
+		for b := 0; b < len(refs); b++ {
+			variance += (refLogDisps[b] - step.RefLogDisp) * (refLogDisps[b] - step.RefLogDisp)
+		}
+
+		step.SE = math.Sqrt(variance/float64(len(refs))) * math.Sqrt(1+1/float64(len(refs)))
+		steps = append(steps, step)
+	}
+
+	rpt := GapReport{Steps: steps, K: kMax}
+	for i := 0; i+1 < len(steps); i++ {
+		if steps[i+1].Gap-steps[i+1].SE <= steps[i].Gap {
+			rpt.K = steps[i].K
+			break
+		}
+	}
+
+	return rpt, nil
+}
+
+// logDisp returns the log of the within-cluster dispersion of a model
+// on a given data set.
+func logDisp(mdl Model, data []Point) float64 {
+	return math.Log(sum(mdl.Errs(data...)))
+}
+
+// newBoxSampler returns a function sampling n points uniformly over the
+// bounding box of a data set, or over the box aligned with its
+// principal components if pca is set.
+func newBoxSampler(pca bool, data []Point) func(rnd *rand.Rand, n int) []Point {
+	var (
+		dims   = len(data[0])
+		center = make(Point, dims)
+		axes   []Point
+	)
+
+	if pca {
+		center = Add(data...)
+		center.ScalMult(1.0 / float64(len(data)))
+		axes = principalAxes(center, data)
+	} else {
+		// The standard basis
+		for i := 0; i < dims; i++ {
+			axes = append(axes, make(Point, dims))
+			axes[i][i] = 1
+		}
+	}
+
+	var (
+		lo = make(Point, dims)
+		hi = make(Point, dims)
+	)
+
+	for i := 0; i < dims; i++ {
+		lo[i], hi[i] = math.Inf(1), math.Inf(-1)
+	}
+
+	for j := 0; j < len(data); j++ {
+		p := data[j].Copy()
+		p.Sub(center)
+		for i := 0; i < dims; i++ {
+			proj := p.Dot(axes[i])
+			lo[i], hi[i] = math.Min(lo[i], proj), math.Max(hi[i], proj)
+		}
+	}
+
+	return func(rnd *rand.Rand, n int) []Point {
+		ps := make([]Point, 0, n)
+		for j := 0; j < n; j++ {
+			p := center.Copy()
+			for i := 0; i < dims; i++ {
+				p.Add(ScalMult(axes[i], lo[i]+(hi[i]-lo[i])*rnd.Float64()))
+			}
+
+			ps = append(ps, p)
+		}
+
+		return ps
+	}
+}
+
+// principalAxes returns the unit eigenvectors of the covariance of a
+// data set about its center, found by the cyclic Jacobi method.
+func principalAxes(center Point, data []Point) []Point {
+	var (
+		dims = len(center)
+		cov  = make([][]float64, dims)
+		vecs = make([][]float64, dims)
+	)
+
+	for i := 0; i < dims; i++ {
+		cov[i] = make([]float64, dims)
+		vecs[i] = make([]float64, dims)
+		vecs[i][i] = 1
+	}
+
+	for j := 0; j < len(data); j++ {
+		for r := 0; r < dims; r++ {
+			for c := 0; c < dims; c++ {
+				cov[r][c] += (data[j][r] - center[r]) * (data[j][c] - center[c])
+			}
+		}
+	}
+
+	// Each rotation zeroes an off-diagonal entry. Sweeps repeat until
+	// the off-diagonal entries are negligible.
+	for sweep := 0; sweep < 100; sweep++ {
+		var off, diag float64
+		for r := 0; r < dims; r++ {
+			diag += cov[r][r] * cov[r][r]
+			for c := r + 1; c < dims; c++ {
+				off += cov[r][c] * cov[r][c]
+			}
+		}
+
+		if off <= 1e-24*diag {
+			break
+		}
+
+		for p := 0; p < dims; p++ {
+			for q := p + 1; q < dims; q++ {
+				if cov[p][q] == 0 {
+					continue
+				}
+
+				var (
+					theta = (cov[q][q] - cov[p][p]) / (2 * cov[p][q])
+					t     = math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+					cos   = 1 / math.Sqrt(t*t+1)
+					sin   = t * cos
+				)
+
+				for i := 0; i < dims; i++ {
+					cov[i][p], cov[i][q] = cos*cov[i][p]-sin*cov[i][q], sin*cov[i][p]+cos*cov[i][q]
+				}
+
+				for i := 0; i < dims; i++ {
+					cov[p][i], cov[q][i] = cos*cov[p][i]-sin*cov[q][i], sin*cov[p][i]+cos*cov[q][i]
+				}
+
+				for i := 0; i < dims; i++ {
+					vecs[i][p], vecs[i][q] = cos*vecs[i][p]-sin*vecs[i][q], sin*vecs[i][p]+cos*vecs[i][q]
+				}
+			}
+		}
+	}
+
+	// The eigenvectors are the columns of the accumulated rotations
+	axes := make([]Point, 0, dims)
+	for c := 0; c < dims; c++ {
+		axis := make(Point, 0, dims)
+		for r := 0; r < dims; r++ {
+			axis = append(axis, vecs[r][c])
+		}
+
+		axes = append(axes, axis)
+	}
+
+	return axes
+}
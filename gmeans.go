@@ -0,0 +1,212 @@
+package kmeans
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// --------------------------------------------------------------------
+//    G-means (Hamerly and Elkan, 2003)
+// --------------------------------------------------------------------
+// 1. Train a model with the fewest number of means allowed.
+// 2. Split each cluster in two by 2-means and project each point in
+//    the cluster onto the direction v between the two child means.
+//    That is, x' = <x, v> / <v, v>.
+// 3. Standardize the projections to zero mean and unit variance and
+//    compute the Anderson-Darling statistic A^2 comparing them to a
+//    normal distribution, corrected for the estimated mean and
+//    variance by Stephens. That is, A*^2 = A^2(1 + 0.75/n + 2.25/n^2).
+// 4. Keep the splits of clusters whose projections are not normal at
+//    the significance level, least normal first, while there are at
+//    most the greatest number of means allowed.
+// 5. Return the model once no split is kept or there are the greatest
+//    number of means allowed, otherwise train the model of the kept
+//    means on the full data set and go to step 2.
+// --------------------------------------------------------------------
+//  * Learning the k in k-means.
+//    https://papers.nips.cc/paper/2526-learning-the-k-in-k-means.pdf
+//  * Goodness-of-fit techniques (D'Agostino and Stephens, 1986) for
+//    the p-value of the statistic.
+// --------------------------------------------------------------------
+
+// ADTest is the result of an Anderson-Darling test of whether a
+// cluster is Gaussian, given the size of the cluster, the statistic
+// A*^2 (corrected by Stephens for the estimated mean and variance), and
+// its p-value. A cluster too small or too uniform to be tested has a
+// statistic of zero and a p-value of one.
+type ADTest struct {
+	Size   int
+	Stat   float64
+	PValue float64
+}
+
+// GMeans returns the model trained by G-means with between kMin and
+// kMax means, along with the Anderson-Darling test of each cluster of
+// the model. Each model and split is trained with the configured
+// options, keeping the highest scoring of the configured number of
+// training rounds, and clusters are split if their test is rejected at
+// the configured significance level.
+func GMeans(kMin, kMax int, data []Point, opts ...Option) (Model, []ADTest, error) {
+	return GMeansContext(context.Background(), kMin, kMax, data, opts...)
+}
+
+// GMeansContext returns a model and tests as GMeans does, but stops
+// once the context is canceled. If canceled, the model trained so far
+// is returned with the context's error.
+func GMeansContext(ctx context.Context, kMin, kMax int, data []Point, opts ...Option) (Model, []ADTest, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if kMax < kMin {
+		return nil, nil, fmt.Errorf("%w: %d to %d", ErrK, kMin, kMax)
+	}
+
+	if err := validate(kMin, data); err != nil {
+		return nil, nil, err
+	}
+
+	if err := validate(kMax, data); err != nil {
+		return nil, nil, err
+	}
+
+	mdl, _, err := newModel(ctx, kMin, data, cfg, false)
+	if err != nil {
+		return mdl, nil, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return mdl, nil, err
+		}
+
+		type split struct {
+			class    int
+			children Model
+		}
+
+		var (
+			clusters = mdl.clusters(cfg.Metric, data)
+			tests    = make([]ADTest, len(mdl))
+			splits   []split
+		)
+
+		for i := 0; i < len(clusters); i++ {
+			tests[i] = ADTest{Size: len(clusters[i]), PValue: 1}
+			if len(clusters[i]) < 2 {
+				continue
+			}
+
+			children, _, err := newModel(ctx, 2, clusters[i], cfg, false)
+			if err != nil {
+				return mdl, nil, err
+			}
+
+			if len(children) < 2 {
+				continue
+			}
+
+			if tests[i] = adTest(children, clusters[i]); tests[i].PValue < cfg.Significance {
+				splits = append(splits, split{class: i, children: children})
+			}
+		}
+
+		if len(splits) == 0 || kMax <= len(mdl) {
+			return mdl, tests, nil
+		}
+
+		sort.SliceStable(splits, func(i, j int) bool { return tests[splits[j].class].Stat < tests[splits[i].class].Stat })
+		if kMax-len(mdl) < len(splits) {
+			splits = splits[:kMax-len(mdl)]
+		}
+
+		next := mdl.Copy()
+		for _, s := range splits {
+			copy(next[s.class], s.children[0])
+			next = append(next, s.children[1])
+		}
+
+		var (
+			meanDists = newTriMatrix(len(next))
+			cls       = make(classes, len(data))
+		)
+
+		meanDists.update(cfg.Metric, next)
+		if next.train(newMonitor(ctx, cfg, 0, next, nil), meanDists, cls, data) == Canceled {
+			return next, nil, ctx.Err()
+		}
+
+		mdl = next
+	}
+}
+
+// adTest returns the Anderson-Darling test of whether a cluster is
+// Gaussian along the direction between the two means of a model
+// splitting it.
+func adTest(children Model, cluster []Point) ADTest {
+	var (
+		n     = float64(len(cluster))
+		v     = children[0].Copy()
+		projs = make([]float64, 0, len(cluster))
+	)
+
+	v.Sub(children[1])
+	sqMag := v.Dot(v)
+	if len(cluster) < 3 || sqMag == 0 {
+		return ADTest{Size: len(cluster), PValue: 1}
+	}
+
+	var mean, variance float64
+	for i := 0; i < len(cluster); i++ {
+		projs = append(projs, cluster[i].Dot(v)/sqMag)
+		mean += projs[i]
+	}
+
+	mean /= n
+	for i := 0; i < len(projs); i++ {
+		variance += (projs[i] - mean) * (projs[i] - mean)
+	}
+
+	if variance /= n - 1; variance == 0 {
+		return ADTest{Size: len(cluster), PValue: 1}
+	}
+
+	sd := math.Sqrt(variance)
+	for i := 0; i < len(projs); i++ {
+		projs[i] = (projs[i] - mean) / sd
+	}
+
+	// A^2 = -n - 1/n sum((2i-1)(ln F(zi) + ln(1-F(z(n+1-i)))))
+	sort.Float64s(projs)
+	stat := -n
+	for i := 0; i < len(projs); i++ {
+		stat -= float64(2*i+1) * (logNormCDF(projs[i]) + logNormCDF(-projs[len(projs)-1-i])) / n
+	}
+
+	stat *= 1 + 0.75/n + 2.25/(n*n)
+	return ADTest{Size: len(cluster), Stat: stat, PValue: adPValue(stat)}
+}
+
+// logNormCDF returns the natural logarithm of the standard normal
+// cumulative distribution function at z.
+func logNormCDF(z float64) float64 {
+	return math.Log(math.Max(0.5*math.Erfc(-z/math.Sqrt2), math.SmallestNonzeroFloat64))
+}
+
+// adPValue returns the p-value of an Anderson-Darling statistic A*^2
+// testing normality with an estimated mean and variance.
+func adPValue(stat float64) float64 {
+	switch {
+	case 0.6 <= stat:
+		return math.Exp(1.2937 - 5.709*stat + 0.0186*stat*stat)
+	case 0.34 <= stat:
+		return math.Exp(0.9177 - 4.279*stat - 1.38*stat*stat)
+	case 0.2 <= stat:
+		return 1 - math.Exp(-8.318+42.796*stat-59.938*stat*stat)
+	default:
+		return 1 - math.Exp(-13.436+101.14*stat-223.73*stat*stat)
+	}
+}
@@ -0,0 +1,123 @@
+package kmeans
+
+import "math"
+
+// --------------------------------------------------------------------
+//    Hamerly's algorithm
+// --------------------------------------------------------------------
+// 1. Assign each point to its nearest mean, recording an upper bound
+//    u(x) on the distance from x to its mean and a single lower bound
+//    l(x) on the distance from x to its second nearest mean.
+// 2. Update the means and measure how far each mean drifted.
+// 3. Loosen the bounds by the drift: u(x) grows by the drift of its
+//    own mean and l(x) shrinks by the largest drift of any other mean.
+// 4. Skip x if u(x) is at most the larger of l(x) and half the
+//    distance from its mean to the nearest other mean. Otherwise,
+//    tighten u(x) and check again before computing the distance from
+//    x to every mean.
+// 5. Return when no reassignments are made, otherwise go to step 2.
+// --------------------------------------------------------------------
+// Compared to Elkan's algorithm, only two bounds are stored per data
+// point, which suits data with few dimensions.
+// --------------------------------------------------------------------
+
+// trainHamerly updates the means using the given data set and mean
+// distance lookup table, stopping as the monitor determines. The
+// resulting means are the same as those produced by trainLloyd.
+func (mdl Model) trainHamerly(mon *monitor, meanDists triMatrix, cls classes, data []Point) StopReason {
+	var (
+		k            = len(mdl)
+		upper        = make([]float64, len(data))
+		lower        = make([]float64, len(data))
+		halfMinDists = make([]float64, k)
+		drifts       = make([]float64, k)
+		acc          = newAccumulator(mon.cfg, k, len(mdl[0]), len(data))
+	)
+
+	// Each data point's bounds are independent of the others, so the
+	// data is shared among the configured number of step workers
+	changes := countChunks(mon.cfg.StepWorkers, len(data), func(lo, hi int) int {
+		var changes int
+		for i := lo; i < hi; i++ {
+			class := cls[i]
+			if cls[i], upper[i], lower[i] = mdl.classDistSecond(mon.cfg.Metric, data[i]); cls[i] != class {
+				changes++
+			}
+		}
+
+		return changes
+	})
+
+	for mon.assigned(mdl, changes); 0 < changes; mon.assigned(mdl, changes) {
+		mon.before(mdl)
+		repairs := acc.update(mdl, cls, data)
+		meanDists.update(mon.cfg.Metric, mdl)
+		if rsn := mon.after(mdl, data, repairs); rsn != 0 {
+			return rsn
+		}
+
+		var maxDrift, nextMaxDrift, maxClass = 0.0, 0.0, 0
+		for j := 0; j < k; j++ {
+			drifts[j] = mon.cfg.Metric.Dist(mon.prevMdl[j], mdl[j])
+			halfMinDists[j] = halfMinDist(meanDists, j, k)
+			switch {
+			case maxDrift < drifts[j]:
+				maxDrift, nextMaxDrift, maxClass = drifts[j], maxDrift, j
+			case nextMaxDrift < drifts[j]:
+				nextMaxDrift = drifts[j]
+			}
+		}
+
+		changes = countChunks(mon.cfg.StepWorkers, len(data), func(lo, hi int) int {
+			var changes int
+			for i := lo; i < hi; i++ {
+				class := cls[i]
+				upper[i] += drifts[class]
+				if class == maxClass {
+					lower[i] -= nextMaxDrift
+				} else {
+					lower[i] -= maxDrift
+				}
+
+				bound := math.Max(halfMinDists[class], lower[i])
+				if upper[i] <= bound {
+					continue
+				}
+
+				if upper[i] = mon.cfg.Metric.Dist(mdl[class], data[i]); upper[i] <= bound {
+					continue
+				}
+
+				if cls[i], upper[i], lower[i] = mdl.classDistSecond(mon.cfg.Metric, data[i]); cls[i] != class {
+					changes++
+				}
+			}
+
+			return changes
+		})
+	}
+
+	return Converged
+}
+
+// classDistSecond returns the classification and distance by a given
+// metric between a data point and its mean, as well as the distance
+// between the data point and the second nearest mean.
+func (mdl Model) classDistSecond(metric Metric, datum Point) (int, float64, float64) {
+	var (
+		class      int
+		minDist    = metric.Dist(mdl[class], datum)
+		secondDist = math.MaxFloat64
+	)
+
+	for i := 1; i < len(mdl); i++ {
+		switch dist := metric.Dist(mdl[i], datum); {
+		case dist < minDist:
+			class, minDist, secondDist = i, dist, minDist
+		case dist < secondDist:
+			secondDist = dist
+		}
+	}
+
+	return class, minDist, secondDist
+}
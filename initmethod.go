@@ -9,12 +9,31 @@ const (
 	Random InitMethod = 1 + iota
 
 	// PlusPlus indicates a model will be initialized with the
-	// k-means++ method.
+	// farthest-first method. Each mean after the first is the data
+	// point farthest from any initialized mean, which is sensitive to
+	// outliers.
 	PlusPlus
 
 	// FirstK indicates a model will be initialized with the first k
 	// data points.
 	FirstK
+
+	// D2 indicates a model will be initialized with the k-means++
+	// method. Each mean after the first is a data point sampled with
+	// probability proportional to its squared distance from the
+	// nearest initialized mean.
+	D2
+
+	// GreedyD2 indicates a model will be initialized with the greedy
+	// k-means++ method. Several candidates are sampled for each mean
+	// as in D2, keeping the candidate that most reduces the sum of
+	// squared distances to the nearest initialized means.
+	GreedyD2
+
+	// Scalable indicates a model will be initialized with the k-means||
+	// method. Candidates are oversampled by D² sampling over a few
+	// passes through the data, then reclustered into k means.
+	Scalable
 )
 
 // String describes an initialization method.
@@ -26,7 +45,18 @@ func (mthd InitMethod) String() string {
 		return "k-means++"
 	case FirstK:
 		return "first-k"
+	case D2:
+		return "d2"
+	case GreedyD2:
+		return "greedy-d2"
+	case Scalable:
+		return "k-means||"
 	default:
 		return "invalid"
 	}
 }
+
+// valid determines if an initialization method is defined.
+func (mthd InitMethod) valid() bool {
+	return Random <= mthd && mthd <= Scalable
+}
@@ -1,6 +1,10 @@
 package kmeans
 
 import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
 	"sort"
 	"testing"
 )
@@ -65,9 +69,9 @@ func TestClass(t *testing.T) {
 		}
 
 		meanDists := newTriMatrix(mdl.K())
-		meanDists.update(mdl.Means())
+		meanDists.update(Euclidean{}, mdl.Means())
 
-		if rec, _ := mdl.classDistMem(test.pnt, meanDists); test.exp != rec {
+		if rec, _ := mdl.classDistMem(Euclidean{}, test.pnt, meanDists); test.exp != rec {
 			t.Errorf("\nexpected %d\nreceived %d\n", test.exp, rec)
 		}
 	}
@@ -160,4 +164,1117 @@ func TestNew(t *testing.T) {
 
 		t.Logf("\nKMeans.PlusPlus: %v\n", recMeans)
 	}
+
+	for _, mthd := range []InitMethod{D2, GreedyD2, Scalable} {
+		var (
+			expMeans = []Point{
+				{4.0 / 3.0, 14.0 / 3.0},
+				{2.0, 4.0 / 3.0},
+				{19.0 / 4.0, 3.0},
+			}
+			mdl      = New(3, data, SetInitMethod(mthd), SetTrainRounds(5))
+			recMeans = mdl.Means()
+		)
+
+		sort.Slice(recMeans, func(i, j int) bool { return recMeans[i].Compare(recMeans[j]) < 0 })
+
+		if len(expMeans) != len(recMeans) {
+			t.Errorf("\nexpected %v\nreceived %v\n", expMeans, recMeans)
+		} else {
+			for i := 0; i < len(expMeans); i++ {
+				if !expMeans[i].Near(recMeans[i], tol) {
+					t.Errorf("\n%s: expected %v\nreceived %v\n", mthd, expMeans[i], recMeans[i])
+				}
+			}
+		}
+
+		t.Logf("\nKMeans.%s: %v\n", mthd, recMeans)
+	}
+}
+
+func TestScalableFewCandidates(t *testing.T) {
+	var (
+		data = []Point{{1, 1}, {1, 2}, {2, 1}, {5, 5}, {5, 6}, {6, 5}}
+		same = []Point{{3, 3}, {3, 3}, {3, 3}, {3, 3}}
+	)
+
+	// No sampling rounds leaves a single candidate
+	mdl, err := Fit(3, data, SetInitMethod(Scalable), SetInitRounds(0))
+	if err != nil {
+		t.Fatalf("\nexpected %v\nreceived %v\n", nil, err)
+	}
+
+	if len(mdl) != 3 {
+		t.Errorf("\nexpected %v\nreceived %v\n", 3, len(mdl))
+	}
+
+	// Identical data points leave no potential to sample from
+	mdl, err = Fit(2, same, SetInitMethod(Scalable))
+	if err != nil {
+		t.Fatalf("\nexpected %v\nreceived %v\n", nil, err)
+	}
+
+	for i := 0; i < len(mdl); i++ {
+		if !mdl[i].Equals(same[0]) {
+			t.Errorf("\nexpected %v\nreceived %v\n", same[0], mdl[i])
+		}
+	}
+}
+
+func TestPlusPlus(t *testing.T) {
+	var (
+		data      = randData(rand.New(rand.NewSource(11)), 200, 2, 4)
+		cfg       = NewConfig(SetInitMethod(PlusPlus), SetSeed(11))
+		mdl       = Model{{0, 0}, {0, 0}, {0, 0}, {0, 0}}
+		meanDists = newTriMatrix(len(mdl))
+	)
+
+	// The means of a previous round are ignored when initializing the
+	// next; each mean after the first is the data point farthest from
+	// the means chosen before it.
+	mdl.init(cfg, meanDists, data)
+	mdl.init(cfg, meanDists, data)
+	for i := 1; i < len(mdl); i++ {
+		var (
+			exp     Point
+			maxDist float64
+		)
+
+		for j := 0; j < len(data); j++ {
+			if _, dist := mdl[:i].classDist(Euclidean{}, data[j]); maxDist < dist {
+				exp, maxDist = data[j], dist
+			}
+		}
+
+		if !exp.Equals(mdl[i]) {
+			t.Errorf("\nexpected %v\nreceived %v\n", exp, mdl[i])
+		}
+	}
+}
+
+func TestTrainMethods(t *testing.T) {
+	const tol = 1e-09
+	var (
+		data = randData(rand.New(rand.NewSource(1)), 2000, 4, 8)
+		exp  = New(8, data, SetInitMethod(FirstK), SetTrainMethod(Lloyd))
+	)
+
+	for _, mthd := range []TrainMethod{Lloyd, Elkan, Hamerly} {
+		rec := New(8, data, SetInitMethod(FirstK), SetTrainMethod(mthd))
+		for i := 0; i < exp.K(); i++ {
+			if !exp[i].Near(rec[i], tol) {
+				t.Errorf("\n%s: expected %v\nreceived %v\n", mthd, exp[i], rec[i])
+			}
+		}
+
+		rec = Model(data[:exp.K()]).Copy()
+		rec.TrainWith(data, SetTrainMethod(mthd))
+		for i := 0; i < exp.K(); i++ {
+			if !exp[i].Near(rec[i], tol) {
+				t.Errorf("\n%s: expected %v\nreceived %v\n", mthd, exp[i], rec[i])
+			}
+		}
+
+		rec = New(8, data, SetInitMethod(FirstK), SetTrainMethod(mthd), SetIncremental(true))
+		for i := 0; i < exp.K(); i++ {
+			if !exp[i].Near(rec[i], tol) {
+				t.Errorf("\nincremental %s: expected %v\nreceived %v\n", mthd, exp[i], rec[i])
+			}
+		}
+	}
+}
+
+// randData returns n points of a given dimension scattered about k
+// random centers.
+func randData(rnd *rand.Rand, n, dims, k int) []Point {
+	centers := make([]Point, 0, k)
+	for i := 0; i < k; i++ {
+		center := make(Point, 0, dims)
+		for j := 0; j < dims; j++ {
+			center = append(center, 100*rnd.Float64())
+		}
+
+		centers = append(centers, center)
+	}
+
+	data := make([]Point, 0, n)
+	for i := 0; i < n; i++ {
+		p := centers[rnd.Intn(k)].Copy()
+		for j := 0; j < dims; j++ {
+			p[j] += 10 * rnd.NormFloat64()
+		}
+
+		data = append(data, p)
+	}
+
+	return data
+}
+
+func TestMiniBatch(t *testing.T) {
+	var (
+		data   = randData(rand.New(rand.NewSource(1)), 10000, 4, 8)
+		expMdl = New(8, data, SetInitMethod(FirstK))
+		recMdl = New(8, data, SetInitMethod(FirstK), SetTrainMethod(MiniBatch), SetBatchSize(200))
+	)
+
+	// Mini-batch means only approximate Lloyd's, so allow some error
+	if exp, rec := expMdl.Score(data...), recMdl.Score(data...); rec < 1.05*exp {
+		t.Errorf("\nexpected score near %f\nreceived %f\n", exp, rec)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	data := randData(rand.New(rand.NewSource(1)), 1000, 3, 5)
+	for _, initMthd := range []InitMethod{Random, PlusPlus, D2, GreedyD2, Scalable} {
+		for _, trainMthd := range []TrainMethod{Lloyd, Elkan, Hamerly, MiniBatch} {
+			var (
+				exp = New(5, data, SetSeed(7), SetInitMethod(initMthd), SetTrainMethod(trainMthd), SetTrainRounds(4), SetWorkers(1))
+				rec = New(5, data, SetSeed(7), SetInitMethod(initMthd), SetTrainMethod(trainMthd), SetTrainRounds(4), SetWorkers(3))
+			)
+
+			for i := 0; i < exp.K(); i++ {
+				if !exp[i].Equals(rec[i]) {
+					t.Errorf("\n%s, %s: expected %v\nreceived %v\n", initMthd, trainMthd, exp[i], rec[i])
+				}
+			}
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		k    int
+		data []Point
+		opts []Option
+		exp  error
+	}{
+		{
+			k:    2,
+			data: []Point{{1.0}, {2.0}, {3.0}},
+			exp:  nil,
+		},
+		{
+			k:    0,
+			data: []Point{{1.0}, {2.0}, {3.0}},
+			exp:  ErrK,
+		},
+		{
+			k:    4,
+			data: []Point{{1.0}, {2.0}, {3.0}},
+			exp:  ErrDataSize,
+		},
+		{
+			k:    2,
+			data: []Point{{1.0}, {2.0, 2.0}, {3.0}},
+			exp:  ErrDims,
+		},
+		{
+			k:    2,
+			data: []Point{{1.0}, {math.NaN()}, {3.0}},
+			exp:  ErrNonFinite,
+		},
+		{
+			k:    2,
+			data: []Point{{1.0}, {math.Inf(1)}, {3.0}},
+			exp:  ErrNonFinite,
+		},
+		{
+			k:    2,
+			data: []Point{{1.0}, {2.0}, {3.0}},
+			opts: []Option{SetInitMethod(0)},
+			exp:  ErrInitMthd,
+		},
+		{
+			k:    2,
+			data: []Point{{1.0}, {2.0}, {3.0}},
+			opts: []Option{SetTrainMethod(0)},
+			exp:  ErrTrainMthd,
+		},
+		{
+			k:    2,
+			data: []Point{{1.0}, {2.0}, {3.0}},
+			opts: []Option{SetTrainRounds(0)},
+			exp:  ErrOption,
+		},
+	}
+
+	for _, test := range tests {
+		if _, rec := Fit(test.k, test.data, test.opts...); !errors.Is(rec, test.exp) {
+			t.Errorf("\nexpected %v\nreceived %v\n", test.exp, rec)
+		}
+	}
+}
+
+func TestStopReason(t *testing.T) {
+	data := randData(rand.New(rand.NewSource(1)), 1000, 3, 5)
+	tests := []struct {
+		opts []Option
+		exp  StopReason
+	}{
+		{
+			exp: Converged,
+		},
+		{
+			opts: []Option{SetMaxIters(1)},
+			exp:  MaxIters,
+		},
+		{
+			opts: []Option{SetShiftTol(1.0)},
+			exp:  ShiftTol,
+		},
+		{
+			opts: []Option{SetInertiaTol(1.0)},
+			exp:  InertiaTol,
+		},
+		{
+			opts: []Option{SetTrainMethod(MiniBatch)},
+			exp:  MaxIters,
+		},
+		{
+			opts: []Option{SetTrainMethod(MiniBatch), SetBatchTol(1e+06)},
+			exp:  ShiftTol,
+		},
+	}
+
+	for _, test := range tests {
+		for _, mthd := range []TrainMethod{Lloyd, Elkan, Hamerly} {
+			mdl := Model(data[:5]).Copy()
+			if rec := mdl.TrainWith(data, append([]Option{SetTrainMethod(mthd)}, test.opts...)...); test.exp != rec {
+				t.Errorf("\n%s: expected %s\nreceived %s\n", mthd, test.exp, rec)
+			}
+		}
+	}
+}
+
+func TestFitContext(t *testing.T) {
+	var (
+		data        = randData(rand.New(rand.NewSource(1)), 1000, 3, 5)
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	cancel()
+	for _, mthd := range []TrainMethod{Lloyd, Elkan, Hamerly, MiniBatch} {
+		mdl, err := FitContext(ctx, 5, data, SetTrainMethod(mthd), SetTrainRounds(3))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("\n%s: expected %v\nreceived %v\n", mthd, context.Canceled, err)
+		}
+
+		if mdl.K() != 5 {
+			t.Errorf("\n%s: expected %d means\nreceived %d\n", mthd, 5, mdl.K())
+		}
+	}
+}
+
+func TestTrainContext(t *testing.T) {
+	var (
+		data = []Point{{1, 1}, {1, 2}, {5, 5}, {5, 6}}
+		ctx  = context.Background()
+	)
+
+	tests := []struct {
+		mdl  Model
+		data []Point
+		opts []Option
+		exp  error
+	}{
+		{mdl: Model{{1, 1}, {5, 5}}, data: data, opts: []Option{SetTrainMethod(0)}, exp: ErrTrainMthd},
+		{mdl: Model{{1, 1}, {5, 5}}, data: data, opts: []Option{SetMetric(nil)}, exp: ErrOption},
+		{mdl: Model{}, data: data, exp: ErrK},
+		{mdl: Model{{1}, {5}}, data: data, exp: ErrDims},
+		{mdl: Model{{1, 1}, {5, 5}}, data: []Point{{1, 1}, {1, 2, 3}}, exp: ErrDims},
+		{mdl: Model{{1, 1}, {5, math.NaN()}}, data: data, exp: ErrNonFinite},
+		{mdl: Model{{1, 1}, {5, 5}}, data: []Point{{1, 1}, {math.Inf(1), 2}}, exp: ErrNonFinite},
+	}
+
+	for _, test := range tests {
+		if _, err := test.mdl.TrainContext(ctx, test.data, test.opts...); !errors.Is(err, test.exp) {
+			t.Errorf("\nexpected %v\nreceived %v\n", test.exp, err)
+		}
+	}
+
+	mdl := Model{{1, 1}, {5, 5}}
+	if rsn, err := mdl.TrainContext(ctx, data); err != nil || rsn != Converged {
+		t.Errorf("\nexpected %v\nreceived %v, %v\n", Converged, rsn, err)
+	}
+
+	// Training on no data is a no-op
+	if rsn, err := mdl.TrainContext(ctx, nil); err != nil || rsn != Converged {
+		t.Errorf("\nexpected %v\nreceived %v, %v\n", Converged, rsn, err)
+	}
+
+	mdl.Train()
+	if exp, rec := (Model{{1, 1.5}, {5, 5.5}}), mdl; exp.String() != rec.String() {
+		t.Errorf("\nexpected %v\nreceived %v\n", exp, rec)
+	}
+}
+
+func TestFitReport(t *testing.T) {
+	data := randData(rand.New(rand.NewSource(1)), 1000, 3, 5)
+	for _, mthd := range []TrainMethod{Lloyd, Elkan, Hamerly} {
+		mdl, rpt, err := FitReport(context.Background(), 5, data, SetTrainMethod(mthd), SetTrainRounds(3), SetSeed(7))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rpt.Rounds) != 3 {
+			t.Fatalf("\n%s: expected %d rounds\nreceived %d\n", mthd, 3, len(rpt.Rounds))
+		}
+
+		if exp, rec := mdl.Score(data...), rpt.Rounds[rpt.Best].Score; exp != rec {
+			t.Errorf("\n%s: expected best score %f\nreceived %f\n", mthd, exp, rec)
+		}
+
+		for _, round := range rpt.Rounds {
+			if round.Stop != Converged || round.Iters != len(round.Inertias) || round.Iters+1 != len(round.Reassigns) {
+				t.Errorf("\n%s: unexpected round report %+v\n", mthd, round)
+			}
+
+			for i := 1; i < len(round.Inertias); i++ {
+				if round.Inertias[i-1] < round.Inertias[i] {
+					t.Errorf("\n%s: expected non-increasing inertias\nreceived %v\n", mthd, round.Inertias)
+				}
+			}
+		}
+	}
+}
+
+// countObserver counts each notification, stopping training once a
+// maximum number of updates is reached in a round.
+type countObserver struct {
+	maxUpdates                      int
+	starts, assigns, updates, dones int
+	stops                           []StopReason
+}
+
+func (obs *countObserver) RoundStarted(round int, mdl Model) {
+	obs.starts++
+}
+
+func (obs *countObserver) Assigned(round int, mdl Model, reassigns int) {
+	obs.assigns++
+}
+
+func (obs *countObserver) Updated(round int, mdl Model, inertia float64) bool {
+	obs.updates++
+	return obs.updates%obs.maxUpdates != 0
+}
+
+func (obs *countObserver) RoundDone(round int, mdl Model, inertia float64, rsn StopReason) {
+	obs.dones++
+	obs.stops = append(obs.stops, rsn)
+}
+
+func TestObserver(t *testing.T) {
+	var (
+		data = randData(rand.New(rand.NewSource(1)), 1000, 3, 5)
+		obs  = countObserver{maxUpdates: 2}
+	)
+
+	New(5, data, SetObserver(&obs), SetTrainRounds(3), SetInitMethod(FirstK), SetWorkers(1))
+	if obs.starts != 3 || obs.dones != 3 || obs.updates != 6 || obs.assigns != 6 {
+		t.Errorf("\nunexpected notifications %+v\n", obs)
+	}
+
+	for _, rsn := range obs.stops {
+		if rsn != Stopped {
+			t.Errorf("\nexpected %s\nreceived %s\n", Stopped, rsn)
+		}
+	}
+}
+
+func TestStepWorkers(t *testing.T) {
+	data := randData(rand.New(rand.NewSource(1)), 3*chunkSize+1, 3, 5)
+	for _, mthd := range []TrainMethod{Lloyd, Elkan, Hamerly} {
+		exp := New(5, data, SetSeed(3), SetTrainMethod(mthd), SetStepWorkers(1))
+		for _, stepWorkers := range []int{2, 4} {
+			rec := New(5, data, SetSeed(3), SetTrainMethod(mthd), SetStepWorkers(stepWorkers))
+			for i := 0; i < exp.K(); i++ {
+				if !exp[i].Equals(rec[i]) {
+					t.Errorf("\n%s %d step workers: expected %v\nreceived %v\n", mthd, stepWorkers, exp[i], rec[i])
+				}
+			}
+		}
+	}
+	// The number of chunks is capped regardless of the size of the data
+	// set, and the chunks cover every data point exactly once
+	for _, n := range []int{0, 1, chunkSize, chunkSize + 1, maxChunks * chunkSize, maxChunks*chunkSize + 1, 1000003} {
+		if maxChunks < chunks(n) {
+			t.Errorf("\n%d points: expected at most %d chunks\nreceived %d\n", n, maxChunks, chunks(n))
+		}
+
+		var total int
+		forChunks(1, n, func(chunk, lo, hi int) { total += hi - lo })
+		if total != n {
+			t.Errorf("\n%d points: expected %d covered\nreceived %d\n", n, n, total)
+		}
+	}
+}
+
+func TestRepairMethod(t *testing.T) {
+	// The second mean duplicates the first, so its cluster is empty
+	data := []Point{{0.0}, {0.0}, {10.0}, {11.0}, {12.0}, {30.0}}
+	tests := []struct {
+		mthd   RepairMethod
+		expK   int
+		expErr error
+	}{
+		{mthd: RepairRandom, expK: 3},
+		{mthd: RepairFarthest, expK: 3},
+		{mthd: RepairSplitLargest, expK: 3},
+		{mthd: RepairSplitMaxErr, expK: 3},
+		{mthd: RepairKeep, expK: 3},
+		{mthd: RepairDrop, expK: 2},
+		{mthd: RepairFail, expK: 3, expErr: ErrEmptyCluster},
+	}
+
+	for _, test := range tests {
+		mdl, rpt, err := FitReport(context.Background(), 3, data, SetInitMethod(FirstK), SetRepairMethod(test.mthd))
+		if !errors.Is(err, test.expErr) {
+			t.Errorf("\n%s: expected %v\nreceived %v\n", test.mthd, test.expErr, err)
+		}
+
+		if test.expK != mdl.K() {
+			t.Errorf("\n%s: expected %d means\nreceived %d\n", test.mthd, test.expK, mdl.K())
+		}
+
+		if rnd := rpt.Rounds[0]; rnd.RepairMthd != test.mthd || rnd.Repairs == 0 {
+			t.Errorf("\n%s: unexpected round report %+v\n", test.mthd, rnd)
+		}
+	}
+
+	// Empty clusters are found by the configured metric. Each point is
+	// nearer the first mean, but in the direction of the second.
+	var (
+		mdl  = Model{{1, 0}, {5, 5}}
+		near = []Point{{0.2, 0.2}, {0.1, 0.3}}
+	)
+
+	if exp, rec := (Model{{1, 0}}), mdl.dropEmpty(Euclidean{}, near); exp.String() != rec.String() {
+		t.Errorf("\nexpected %v\nreceived %v\n", exp, rec)
+	}
+
+	if exp, rec := (Model{{5, 5}}), mdl.dropEmpty(Cosine{}, near); exp.String() != rec.String() {
+		t.Errorf("\nexpected %v\nreceived %v\n", exp, rec)
+	}
+}
+
+func TestMetric(t *testing.T) {
+	p, q := Point{1.0, 2.0}, Point{4.0, 6.0}
+	tests := []struct {
+		metric  Metric
+		exp     float64
+		expCost float64
+	}{
+		{metric: Euclidean{}, exp: 5.0, expCost: 25.0},
+		{metric: SqEuclidean{}, exp: 25.0, expCost: 25.0},
+		{metric: Manhattan{}, exp: 7.0, expCost: 7.0},
+		{metric: Chebyshev{}, exp: 4.0, expCost: 16.0},
+		{metric: Minkowski{P: 1.0}, exp: 7.0, expCost: 7.0},
+		{metric: Minkowski{P: 2.0}, exp: 5.0, expCost: 25.0},
+		{metric: Cosine{}, exp: 1.0 - 16.0/(math.Sqrt(5.0)*math.Sqrt(52.0)), expCost: 1.0 - 16.0/(math.Sqrt(5.0)*math.Sqrt(52.0))},
+	}
+
+	for _, test := range tests {
+		if rec := test.metric.Dist(p, q); 1e-12 < math.Abs(test.exp-rec) {
+			t.Errorf("\n%T: expected %f\nreceived %f\n", test.metric, test.exp, rec)
+		}
+
+		if rec := test.metric.Cost(test.metric.Dist(p, q)); 1e-9 < math.Abs(test.expCost-rec) {
+			t.Errorf("\n%T: expected cost %f\nreceived %f\n", test.metric, test.expCost, rec)
+		}
+	}
+
+	// Each point is nearer the first mean, but in the direction of the
+	// second
+	var (
+		mdl  = Model{{1, 0}, {5, 5}}
+		near = []Point{{0.2, 0.2}, {0.1, 0.3}}
+	)
+
+	if exp, rec := 0, mdl.Class(near[0]); exp != rec {
+		t.Errorf("\nexpected %d\nreceived %d\n", exp, rec)
+	}
+
+	if exp, rec := 1, mdl.ClassBy(Cosine{}, near[0]); exp != rec {
+		t.Errorf("\nexpected %d\nreceived %d\n", exp, rec)
+	}
+
+	if exp, rec := []int{1, 1}, mdl.ClassesBy(Cosine{}, near...); exp[0] != rec[0] || exp[1] != rec[1] {
+		t.Errorf("\nexpected %v\nreceived %v\n", exp, rec)
+	}
+
+	if exp, rec := []int{0, 2}, mdl.SizesBy(Cosine{}, near...); exp[0] != rec[0] || exp[1] != rec[1] || exp[1] != mdl.SizeBy(Cosine{}, 1, near...) {
+		t.Errorf("\nexpected %v\nreceived %v\n", exp, rec)
+	}
+
+	if exp, rec := 2, len(mdl.ClusterBy(Cosine{}, 1, near...)); exp != rec || exp != len(mdl.ClustersBy(Cosine{}, near...)[1]) {
+		t.Errorf("\nexpected %d\nreceived %d\n", exp, rec)
+	}
+
+	var cost float64
+	for i := 0; i < len(near); i++ {
+		cost += Cosine{}.Dist(mdl[1], near[i])
+	}
+
+	if rec := mdl.ErrBy(Cosine{}, 1, near...); 1e-12 < math.Abs(cost-rec) || 1e-12 < math.Abs(cost-mdl.ErrsBy(Cosine{}, near...)[1]) || 1e-12 < math.Abs(cost+mdl.ScoreBy(Cosine{}, near...)) {
+		t.Errorf("\nexpected %f\nreceived %f\n", cost, rec)
+	}
+
+	// The inertia of the squared Euclidean metric is not squared again
+	_, rpt, err := FitReport(context.Background(), 2, []Point{{0}, {2}, {10}, {12}}, SetInitMethod(FirstK), SetMetric(SqEuclidean{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, rec := 24.0, rpt.Rounds[0].Inertias[0]; exp != rec {
+		t.Errorf("\nexpected %f\nreceived %f\n", exp, rec)
+	}
+
+	data := randData(rand.New(rand.NewSource(7)), 300, 2, 3)
+	for _, mthd := range []TrainMethod{Elkan, Hamerly} {
+		if _, err := Fit(3, data, SetTrainMethod(mthd), SetMetric(Cosine{})); !errors.Is(err, ErrOption) {
+			t.Errorf("\n%s: expected %v\nreceived %v\n", mthd, ErrOption, err)
+		}
+	}
+
+	for _, metric := range []Metric{Manhattan{}, Chebyshev{}} {
+		exp, err := Fit(3, data, SetSeed(7), SetMetric(metric))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, mthd := range []TrainMethod{Elkan, Hamerly} {
+			rec, err := Fit(3, data, SetSeed(7), SetMetric(metric), SetTrainMethod(mthd))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < exp.K(); i++ {
+				if !exp[i].Equals(rec[i]) {
+					t.Errorf("\n%T %s: expected %v\nreceived %v\n", metric, mthd, exp[i], rec[i])
+				}
+			}
+		}
+	}
+}
+
+func TestSpherical(t *testing.T) {
+	var (
+		rnd  = rand.New(rand.NewSource(11))
+		data = make([]Point, 0, 200)
+	)
+
+	// Two directions at varying magnitudes
+	for i := 0; i < 100; i++ {
+		r := 1.0 + 9.0*rnd.Float64()
+		data = append(data, Point{r, r * 0.1 * rnd.Float64()}, Point{r * 0.1 * rnd.Float64(), r})
+	}
+
+	for _, opts := range [][]Option{{}, {SetIncremental(true)}, {SetTrainMethod(MiniBatch)}} {
+		mdl, err := Fit(2, data, append(opts, SetSeed(11), SetInitMethod(D2), SetSpherical(true))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < mdl.K(); i++ {
+			if rec := mdl[i].Mag(); 1e-12 < math.Abs(1.0-rec) {
+				t.Errorf("\nexpected %f\nreceived %f\n", 1.0, rec)
+			}
+		}
+
+		classes := mdl.ClassesSim(data...)
+		for i := 0; i < len(data); i += 2 {
+			if classes[i] == classes[i+1] {
+				t.Errorf("\nexpected %v and %v in different classes\nreceived %d\n", data[i], data[i+1], classes[i])
+			}
+
+			if class, sim := mdl.ClassSim(data[i]); class != classes[i] || sim != mdl.Sim(class, data[i]) {
+				t.Errorf("\nexpected %d, %f\nreceived %d, %f\n", classes[i], mdl.Sim(classes[i], data[i]), class, sim)
+			}
+		}
+
+		if exp, rec := float64(len(data))*0.9, mdl.ScoreSim(data...); rec < exp {
+			t.Errorf("\nexpected at least %f\nreceived %f\n", exp, rec)
+		}
+	}
+
+	if _, err := Fit(2, data, SetSpherical(true), SetMetric(Euclidean{})); !errors.Is(err, ErrOption) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrOption, err)
+	}
+}
+
+func TestMedoids(t *testing.T) {
+	var (
+		rnd  = rand.New(rand.NewSource(5))
+		data = randData(rnd, 12, 2, 3)
+	)
+
+	// Find the least cost over every pair of medoids
+	minCost := math.Inf(1)
+	for i := 0; i < len(data); i++ {
+		for j := i + 1; j < len(data); j++ {
+			minCost = math.Min(minCost, Medoids{i, j}.Cost(Manhattan{}, data))
+		}
+	}
+
+	for _, mthd := range []MedoidMethod{Alternate, PAM, FasterPAM} {
+		med, err := FitMedoids(2, data, SetSeed(5), SetTrainRounds(4), SetMetric(Manhattan{}), SetMedoidMethod(mthd))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if med[0] == med[1] || med[0] < 0 || len(data) <= med[0] || med[1] < 0 || len(data) <= med[1] {
+			t.Fatalf("\n%s: expected distinct medoids\nreceived %v\n", mthd, med)
+		}
+
+		if rec := med.Cost(Manhattan{}, data); mthd != Alternate && 1e-9 < rec-minCost {
+			t.Errorf("\n%s: expected %f\nreceived %f\n", mthd, minCost, rec)
+		}
+
+		classes := med.Classes(Manhattan{}, data)
+		for c := 0; c < len(med); c++ {
+			if classes[med[c]] != c {
+				t.Errorf("\n%s: expected %d\nreceived %d\n", mthd, c, classes[med[c]])
+			}
+		}
+	}
+
+	// PAM is deterministic, so it is trained once regardless of the
+	// number of training rounds
+	calls := make([]int, 0, 2)
+	for _, rounds := range []int{1, 3} {
+		var n int
+		dist := func(i, j int) float64 {
+			n++
+			return data[i].Dist(data[j])
+		}
+
+		if _, err := newMedoids(context.Background(), 2, data, NewConfig(SetTrainRounds(rounds), SetMedoidMethod(PAM)), dist); err != nil {
+			t.Fatal(err)
+		}
+
+		calls = append(calls, n)
+	}
+
+	if calls[0] != calls[1] {
+		t.Errorf("\nexpected %d distances\nreceived %d\n", calls[0], calls[1])
+	}
+
+	// No single swap of a medoid with a non-medoid reduces the cost
+	data = randData(rnd, 100, 3, 4)
+	for _, mthd := range []MedoidMethod{PAM, FasterPAM} {
+		med, err := FitMedoids(4, data, SetSeed(5), SetInitMethod(D2), SetMedoidMethod(mthd))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cost := med.Cost(Euclidean{}, data)
+		for c := 0; c < len(med); c++ {
+			for i := 0; i < len(data); i++ {
+				swapped := append(Medoids{}, med...)
+				swapped[c] = i
+				if rec := swapped.Cost(Euclidean{}, data); rec < cost-1e-9 {
+					t.Fatalf("\n%s: expected at least %f\nreceived %f swapping %d for %d\n", mthd, cost, rec, med[c], i)
+				}
+			}
+		}
+	}
+
+	if _, err := FitMedoids(4, data, SetMedoidMethod(0)); !errors.Is(err, ErrMedoidMthd) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrMedoidMthd, err)
+	}
+}
+
+func TestCLARA(t *testing.T) {
+	var (
+		rnd  = rand.New(rand.NewSource(13))
+		data = randData(rnd, 2000, 2, 5)
+	)
+
+	exp, err := FitMedoids(5, data, SetSeed(13), SetInitMethod(D2), SetTrainRounds(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expCost := exp.Cost(Euclidean{}, data)
+	for _, mthd := range []MedoidMethod{CLARA, CLARANS} {
+		for _, k := range []int{1, 5} {
+			med, err := FitMedoids(k, data, SetSeed(13), SetInitMethod(D2), SetMedoidMethod(mthd))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if k != len(med) {
+				t.Fatalf("\n%s: expected %d medoids\nreceived %d\n", mthd, k, len(med))
+			}
+
+			// Sampled and randomized searches should be near FasterPAM
+			if rec := med.Cost(Euclidean{}, data); k == 5 && 1.05*expCost < rec {
+				t.Errorf("\n%s: expected at most %f\nreceived %f\n", mthd, 1.05*expCost, rec)
+			}
+		}
+	}
+
+	if _, err := FitMedoids(5, data, SetMedoidMethod(CLARA), SetSamples(0)); !errors.Is(err, ErrOption) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrOption, err)
+	}
+}
+
+func TestMedians(t *testing.T) {
+	// The outlier pulls the mean of its cluster, but not the median
+	data := []Point{{0.0}, {1.0}, {2.0}, {100.0}, {1000.0}, {1001.0}, {1002.0}}
+	mdl, err := Fit(2, data, SetInitMethod(FirstK), SetMedians(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mdl.Sort()
+	if exp := (Model{{1.5}, {1001.0}}); !exp[0].Equals(mdl[0]) || !exp[1].Equals(mdl[1]) {
+		t.Errorf("\nexpected %v\nreceived %v\n", exp, mdl)
+	}
+
+	data = randData(rand.New(rand.NewSource(17)), 400, 3, 4)
+	for _, mthd := range []InitMethod{Random, PlusPlus, FirstK, D2, GreedyD2, Scalable} {
+		for _, trainMthd := range []TrainMethod{Lloyd, Elkan, Hamerly} {
+			mdl, err := Fit(4, data, SetSeed(17), SetInitMethod(mthd), SetTrainMethod(trainMthd), SetMedians(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var (
+				cls     = classes(mdl.ClassesBy(Manhattan{}, data...))
+				medians = mdl.Copy()
+			)
+
+			medians.medians(cls, data, nil)
+			for i := 0; i < mdl.K(); i++ {
+				if !medians[i].Equals(mdl[i]) {
+					t.Errorf("\n%s %s: expected %v\nreceived %v\n", mthd, trainMthd, medians[i], mdl[i])
+				}
+			}
+		}
+	}
+
+	// Rounds are compared by the sum of unsquared Manhattan distances
+	mdl, rpt, err := FitReport(context.Background(), 4, data, SetSeed(17), SetMedians(true), SetTrainRounds(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cost float64
+	for i := 0; i < len(data); i++ {
+		cost += Manhattan{}.Dist(mdl[mdl.ClassBy(Manhattan{}, data[i])], data[i])
+	}
+
+	if rec := rpt.Rounds[rpt.Best].Score; 1e-9 < math.Abs(cost+rec) {
+		t.Errorf("\nexpected %f\nreceived %f\n", -cost, rec)
+	}
+
+	for _, rnd := range rpt.Rounds {
+		if rpt.Rounds[rpt.Best].Score < rnd.Score {
+			t.Errorf("\nexpected at most %f\nreceived %f\n", rpt.Rounds[rpt.Best].Score, rnd.Score)
+		}
+
+		if rec := rnd.Inertias[len(rnd.Inertias)-1]; 1e-9 < math.Abs(rnd.Score+rec) {
+			t.Errorf("\nexpected %f\nreceived %f\n", -rnd.Score, rec)
+		}
+	}
+
+	for _, opts := range [][]Option{{SetMetric(Euclidean{})}, {SetIncremental(true)}, {SetTrainMethod(MiniBatch)}} {
+		if _, err := Fit(4, data, append([]Option{SetMedians(true)}, opts...)...); !errors.Is(err, ErrOption) {
+			t.Errorf("\nexpected %v\nreceived %v\n", ErrOption, err)
+		}
+	}
+}
+
+func TestBisect(t *testing.T) {
+	data := randData(rand.New(rand.NewSource(19)), 600, 2, 6)
+	for _, mthd := range []BisectMethod{BisectMaxErr, BisectLargest} {
+		mdl, tree, err := Bisect(6, data, SetSeed(19), SetTrainRounds(3), SetInitMethod(D2), SetBisectMethod(mthd))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, rec := 6, mdl.K(); exp != rec {
+			t.Fatalf("\n%s: expected %d\nreceived %d\n", mthd, exp, rec)
+		}
+
+		if exp, rec := 11, len(tree); exp != rec {
+			t.Fatalf("\n%s: expected %d nodes\nreceived %d\n", mthd, exp, rec)
+		}
+
+		// Each split node's size is the sum of its children's sizes
+		for i := 0; i < len(tree); i++ {
+			switch node := tree[i]; {
+			case node.Children == nil:
+				if !node.Mean.Equals(mdl[node.Class]) {
+					t.Errorf("\n%s: expected %v\nreceived %v\n", mthd, node.Mean, mdl[node.Class])
+				}
+			case node.Size != tree[node.Children[0]].Size+tree[node.Children[1]].Size || node.Class != -1:
+				t.Errorf("\n%s: unexpected split node %+v\n", mthd, node)
+			}
+		}
+
+		if tree[0].Size != len(data) || tree[0].Parent != -1 {
+			t.Errorf("\n%s: unexpected root node %+v\n", mthd, tree[0])
+		}
+	}
+
+	if exp, rec := 5, NewConfig().BisectTrials; exp != rec {
+		t.Errorf("\nexpected %d\nreceived %d\n", exp, rec)
+	}
+
+	if _, _, err := Bisect(2, data, SetBisectTrials(0)); !errors.Is(err, ErrOption) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrOption, err)
+	}
+
+	// Each split keeps the highest scoring of several trials, so more
+	// trials never split the root worse
+	for seed := int64(0); seed < 5; seed++ {
+		_, one, err := Bisect(2, data, SetSeed(seed), SetBisectTrials(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, many, err := Bisect(2, data, SetSeed(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, rec := one[1].Err+one[2].Err, many[1].Err+many[2].Err; exp < rec-1e-9 {
+			t.Errorf("\nseed %d: expected at most %f\nreceived %f\n", seed, exp, rec)
+		}
+	}
+
+	// Every point may tie to the first mean, leaving a child empty
+	ties := []Point{{1, 1}, {1, 1}, {1, 1}, {1, 1}, {2, 2}}
+	for seed := int64(0); seed < 50; seed++ {
+		mdl, _, err := Bisect(2, ties, SetSeed(seed))
+		if err != nil {
+			t.Fatalf("\nseed %d: expected %v\nreceived %v\n", seed, nil, err)
+		}
+
+		if mdl.Sort(); !mdl[0].Equals(ties[0]) || !mdl[1].Equals(ties[4]) {
+			t.Errorf("\nseed %d: expected %v\nreceived %v\n", seed, Model{ties[0], ties[4]}, mdl)
+		}
+	}
+
+	if _, _, err := Bisect(3, []Point{{1.0}, {1.0}, {2.0}}); !errors.Is(err, ErrDataSize) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrDataSize, err)
+	}
+}
+
+func TestXMeans(t *testing.T) {
+	data := randData(rand.New(rand.NewSource(23)), 800, 2, 5)
+	mdl, trace, err := XMeans(2, 10, data, SetSeed(23), SetTrainRounds(3), SetInitMethod(D2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, rec := 5, mdl.K(); exp != rec {
+		t.Errorf("\nexpected %d\nreceived %d\ntrace %v\n", exp, rec, trace)
+	}
+
+	if trace[0].K != 2 {
+		t.Errorf("\nexpected %d\nreceived %d\n", 2, trace[0].K)
+	}
+
+	for i := 0; i < len(trace); i++ {
+		if trace[i].K == mdl.K() && 1e-9 < math.Abs(trace[i].BIC-mdl.bic(data)) {
+			t.Errorf("\nexpected %f\nreceived %f\n", mdl.bic(data), trace[i].BIC)
+		}
+	}
+
+	if _, _, err := XMeans(3, 2, data); !errors.Is(err, ErrK) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrK, err)
+	}
+
+	if _, _, err := XMeans(0, 5, data); !errors.Is(err, ErrK) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrK, err)
+	}
+}
+
+func TestGMeans(t *testing.T) {
+	var (
+		rnd  = rand.New(rand.NewSource(29))
+		data = make([]Point, 0, 1500)
+	)
+
+	// Three well separated Gaussian clusters
+	for i := 0; i < cap(data); i++ {
+		center := float64(i%3) * 100
+		data = append(data, Point{center + 5*rnd.NormFloat64(), center + 5*rnd.NormFloat64()})
+	}
+
+	mdl, tests, err := GMeans(1, 10, data, SetSeed(29), SetTrainRounds(3), SetInitMethod(D2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, rec := 3, mdl.K(); exp != rec {
+		t.Fatalf("\nexpected %d\nreceived %d\n", exp, rec)
+	}
+
+	var size int
+	for i := 0; i < len(tests); i++ {
+		if tests[i].PValue < 0.0001 {
+			t.Errorf("\nexpected at least %f\nreceived %f\n", 0.0001, tests[i].PValue)
+		}
+
+		size += tests[i].Size
+	}
+
+	if size != len(data) {
+		t.Errorf("\nexpected %d\nreceived %d\n", len(data), size)
+	}
+
+	if _, _, err := GMeans(0, 5, data); !errors.Is(err, ErrK) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrK, err)
+	}
+
+	// Critical values of A*^2 at a few significance levels
+	for stat, exp := range map[float64]float64{0.631: 0.1, 0.752: 0.05, 1.035: 0.01} {
+		if rec := adPValue(stat); 0.005 < math.Abs(exp-rec) {
+			t.Errorf("\nexpected %f\nreceived %f\n", exp, rec)
+		}
+	}
+}
+
+func TestSweep(t *testing.T) {
+	var (
+		data = []Point{{0.0}, {1.0}, {10.0}, {11.0}}
+		step = Model{{0.5}, {10.5}}.sweepStep(data)
+		exp  = (9.5/10.5 + 8.5/9.5) / 2
+	)
+
+	if 1e-12 < math.Abs(exp-step.Silhouette) {
+		t.Errorf("\nexpected %f\nreceived %f\n", exp, step.Silhouette)
+	}
+
+	// Between: 4*5^2 = 100 over 1, within: 4*0.5^2 = 1 over 2
+	if exp := 200.0; 1e-12 < math.Abs(exp-step.CalinskiHarabasz) {
+		t.Errorf("\nexpected %f\nreceived %f\n", exp, step.CalinskiHarabasz)
+	}
+
+	// Scatters of 0.5 and 0.5 over a distance of 10
+	if exp := 0.1; 1e-12 < math.Abs(exp-step.DaviesBouldin) {
+		t.Errorf("\nexpected %f\nreceived %f\n", exp, step.DaviesBouldin)
+	}
+
+	// Four well separated clusters
+	rnd := rand.New(rand.NewSource(31))
+	data = make([]Point, 0, 400)
+	for i := 0; i < cap(data); i++ {
+		data = append(data, Point{float64(i%2)*100 + 5*rnd.NormFloat64(), float64(i/2%2)*100 + 5*rnd.NormFloat64()})
+	}
+
+	rpt, err := Sweep(1, 8, data, SetSeed(31), SetTrainRounds(3), SetInitMethod(D2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, rec := 8, len(rpt.Steps); exp != rec {
+		t.Fatalf("\nexpected %d\nreceived %d\n", exp, rec)
+	}
+
+	for i := 0; i < len(rpt.Steps); i++ {
+		if exp, rec := i+1, rpt.Steps[i].Model.K(); exp != rec {
+			t.Errorf("\nexpected %d\nreceived %d\n", exp, rec)
+		}
+	}
+
+	var steps []SweepStep
+	for i, inertia := range []float64{100, 40, 15, 10, 8, 7, 6} {
+		steps = append(steps, SweepStep{K: i + 1, Inertia: inertia})
+	}
+
+	if exp, rec := 3, elbow(steps); exp != rec {
+		t.Errorf("\nexpected %d\nreceived %d\n", exp, rec)
+	}
+
+	for i := 0; i < len(rpt.Steps); i++ {
+		if step := rpt.Steps[i]; step.K != 4 && 1 < step.K && (rpt.Steps[3].Silhouette <= step.Silhouette || rpt.Steps[3].CalinskiHarabasz <= step.CalinskiHarabasz || step.DaviesBouldin <= rpt.Steps[3].DaviesBouldin) {
+			t.Errorf("\nexpected %+v\nto be better than %+v\n", rpt.Steps[3], step)
+		}
+	}
+
+	// The same seed reproduces the sweep regardless of workers
+	rec, err := Sweep(1, 8, data, SetSeed(31), SetTrainRounds(3), SetInitMethod(D2), SetWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < len(rec.Steps); i++ {
+		if rpt.Steps[i].Inertia != rec.Steps[i].Inertia {
+			t.Errorf("\nexpected %f\nreceived %f\n", rpt.Steps[i].Inertia, rec.Steps[i].Inertia)
+		}
+	}
+}
+
+func TestGap(t *testing.T) {
+	var (
+		rnd  = rand.New(rand.NewSource(37))
+		data = make([]Point, 0, 300)
+	)
+
+	// Three well separated clusters at the corners of a triangle
+	centers := []Point{{0, 0}, {50, 0}, {25, 40}}
+	for i := 0; i < cap(data); i++ {
+		data = append(data, Point{centers[i%3][0] + 3*rnd.NormFloat64(), centers[i%3][1] + 3*rnd.NormFloat64()})
+	}
+
+	for _, pcaBox := range []bool{false, true} {
+		rpt, err := Gap(1, 6, data, SetSeed(37), SetTrainRounds(3), SetInitMethod(D2), SetPCABox(pcaBox))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp := 3; exp != rpt.K {
+			t.Errorf("\npca box %t: expected %d\nreceived %d\nsteps %+v\n", pcaBox, exp, rpt.K, rpt.Steps)
+		}
+	}
+
+	if _, err := Gap(0, 3, data); !errors.Is(err, ErrK) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrK, err)
+	}
+
+	// Too few distinct points leave the dispersion of some k at zero
+	dups := []Point{{0, 0}, {0, 0}, {0, 0}, {1, 1}, {2, 2}}
+	if _, err := Gap(1, 3, dups); !errors.Is(err, ErrDataSize) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrDataSize, err)
+	}
+
+	// Training errors are returned rather than panicking
+	dups = []Point{{5, 5}, {0, 0}, {0, 0}, {1, 1}, {9, 9}}
+	if _, err := Gap(1, 3, dups, SetInitMethod(FirstK), SetRepairMethod(RepairFail)); !errors.Is(err, ErrEmptyCluster) {
+		t.Errorf("\nexpected %v\nreceived %v\n", ErrEmptyCluster, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GapContext(ctx, 1, 3, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("\nexpected %v\nreceived %v\n", context.Canceled, err)
+	}
+
+	// The first principal axis is the diagonal
+	data = data[:0]
+	for i := 0; i < 100; i++ {
+		x := 100 * rnd.Float64()
+		data = append(data, Point{x + rnd.NormFloat64(), x + rnd.NormFloat64()})
+	}
+
+	axes := principalAxes(ScalMult(Add(data...), 1.0/float64(len(data))), data)
+	for i := 0; i < len(axes); i++ {
+		if rec := axes[i].Mag(); 1e-9 < math.Abs(1-rec) {
+			t.Errorf("\nexpected %f\nreceived %f\n", 1.0, rec)
+		}
+	}
+
+	if rec := math.Abs(axes[0].Dot(axes[1])); 1e-9 < rec {
+		t.Errorf("\nexpected %f\nreceived %f\n", 0.0, rec)
+	}
+
+	if rec := math.Max(math.Abs(axes[0].Dot(Point{math.Sqrt2 / 2, math.Sqrt2 / 2})), math.Abs(axes[1].Dot(Point{math.Sqrt2 / 2, math.Sqrt2 / 2}))); rec < 0.999 {
+		t.Errorf("\nexpected %f\nreceived %f\n", 1.0, rec)
+	}
 }
@@ -0,0 +1,78 @@
+package kmeans
+
+import "sort"
+
+// --------------------------------------------------------------------
+//    k-Medians
+// --------------------------------------------------------------------
+// 1. Assign each point to its nearest median by Manhattan distance.
+// 2. Update each median to the coordinate-wise median of the points
+//    assigned to it. That is, the median of each dimension taken
+//    separately, which minimizes the total Manhattan distance from
+//    the points to the median.
+// 3. Return when no reassignments are made, otherwise go to step 1.
+// --------------------------------------------------------------------
+
+// weightedValue is a value counted as many times as its weight.
+type weightedValue struct {
+	value, weight float64
+}
+
+// medians updates each mean to the coordinate-wise median of its class.
+// Each data point counts as many times as its weight, or once if the
+// weights are nil. Empty classes are left unchanged and returned.
+func (mdl Model) medians(cls classes, data []Point, weights []float64) []int {
+	var (
+		members = make([][]int, len(mdl))
+		empties []int
+	)
+
+	for i := 0; i < len(data); i++ {
+		members[cls[i]] = append(members[cls[i]], i)
+	}
+
+	for i := 0; i < len(mdl); i++ {
+		if len(members[i]) == 0 {
+			empties = append(empties, i)
+			continue
+		}
+
+		values := make([]weightedValue, len(members[i]))
+		for d := 0; d < len(mdl[i]); d++ {
+			for j, m := range members[i] {
+				values[j] = weightedValue{value: data[m][d], weight: 1.0}
+				if weights != nil {
+					values[j].weight = weights[m]
+				}
+			}
+
+			mdl[i][d] = median(values)
+		}
+	}
+
+	return empties
+}
+
+// median returns the weighted median of a list of values, which is
+// sorted in place. If the weight of the values is split evenly between
+// two values, their average is returned.
+func median(values []weightedValue) float64 {
+	sort.Slice(values, func(i, j int) bool { return values[i].value < values[j].value })
+
+	var total float64
+	for i := 0; i < len(values); i++ {
+		total += values[i].weight
+	}
+
+	var cum float64
+	for i := 0; i < len(values)-1; i++ {
+		switch cum += values[i].weight; {
+		case total < 2*cum:
+			return values[i].value
+		case total == 2*cum:
+			return (values[i].value + values[i+1].value) / 2
+		}
+	}
+
+	return values[len(values)-1].value
+}
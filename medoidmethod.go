@@ -0,0 +1,55 @@
+package kmeans
+
+// MedoidMethod defines how a k-medoids model is trained.
+type MedoidMethod uint
+
+const (
+	// Alternate indicates medoids will be trained by alternating
+	// between assigning each point to its nearest medoid and choosing
+	// the point in each cluster nearest to the rest of its cluster as
+	// its medoid (Voronoi iteration).
+	Alternate MedoidMethod = 1 + iota
+
+	// PAM indicates medoids will be built greedily and then improved
+	// by applying the best swap of a medoid with a non-medoid each
+	// iteration (partitioning around medoids).
+	PAM
+
+	// FasterPAM indicates medoids will be improved by eagerly applying
+	// any swap of a medoid with a non-medoid that reduces the total
+	// distance from each point to its nearest medoid.
+	FasterPAM
+
+	// CLARA indicates medoids will be trained by FasterPAM on samples
+	// of the data, keeping the medoids of the sample with the least
+	// total distance over the full data set.
+	CLARA
+
+	// CLARANS indicates medoids will be trained by a randomized search,
+	// swapping a medoid with a random non-medoid if doing so reduces
+	// the total distance, until a number of neighbors fail to.
+	CLARANS
+)
+
+// String describes a medoid method.
+func (mthd MedoidMethod) String() string {
+	switch mthd {
+	case Alternate:
+		return "alternate"
+	case PAM:
+		return "pam"
+	case FasterPAM:
+		return "fasterpam"
+	case CLARA:
+		return "clara"
+	case CLARANS:
+		return "clarans"
+	default:
+		return "invalid"
+	}
+}
+
+// valid determines if a medoid method is defined.
+func (mthd MedoidMethod) valid() bool {
+	return Alternate <= mthd && mthd <= CLARANS
+}
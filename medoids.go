@@ -0,0 +1,511 @@
+package kmeans
+
+import (
+	"context"
+	"math"
+	"math/rand"
+)
+
+// --------------------------------------------------------------------
+//    k-Medoids (alternate, or Voronoi iteration)
+// --------------------------------------------------------------------
+// 1. Initialize k medoids as data points.
+// 2. Assign each point to its nearest medoid.
+// 3. For each cluster, choose the point with the least total distance
+//    to the rest of the cluster as its medoid.
+// 4. Return when no medoid changes, otherwise go to step 2.
+// --------------------------------------------------------------------
+
+// --------------------------------------------------------------------
+//    PAM and FasterPAM (Schubert and Rousseeuw, 2021)
+// --------------------------------------------------------------------
+// 1. Initialize k medoids. PAM builds them greedily, choosing the
+//    point with the least total distance to every other point first,
+//    then each point that most reduces the total distance.
+// 2. Record the nearest and second nearest medoid of each point and
+//    the loss in total distance from removing each medoid.
+// 3. For a non-medoid x, compute the change in total distance from
+//    swapping x with every medoid at once in a single pass over the
+//    points, keeping the medoid with the least change.
+// 4. PAM applies the best swap over every non-medoid, returning when
+//    no swap reduces the total distance. FasterPAM applies any swap
+//    reducing the total distance immediately, returning once every
+//    non-medoid is visited without a swap. Otherwise, go to step 2.
+// --------------------------------------------------------------------
+//  * Fast and eager k-medoids clustering: O(k) runtime improvement of
+//    the PAM, CLARA, and CLARANS algorithms.
+//    https://arxiv.org/abs/2008.05171
+// --------------------------------------------------------------------
+
+// Medoids is a set of k indices into the data set a k-medoids model was
+// trained upon. Each index is the medoid of its cluster. That is, the
+// data point with the least total distance to the rest of the cluster.
+type Medoids []int
+
+// NewMedoids returns a trained k-medoids model. Unless the medoid
+// method is CLARA or CLARANS, each pairwise distance by the configured
+// metric is computed once and cached, so memory grows with the square
+// of the size of the data set. The configured training rounds and
+// maximum iterations are respected, except that PAM is deterministic
+// and so is trained once. The configured initialization method is
+// respected by FasterPAM and CLARANS, but PAM and CLARA build their
+// medoids greedily. NewMedoids panics if the model cannot be trained;
+// see FitMedoids.
+func NewMedoids(k int, data []Point, opts ...Option) Medoids {
+	med, err := FitMedoids(k, data, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return med
+}
+
+// FitMedoids returns a trained k-medoids model as NewMedoids does, but
+// returns an error if the model cannot be trained.
+func FitMedoids(k int, data []Point, opts ...Option) (Medoids, error) {
+	return FitMedoidsContext(context.Background(), k, data, opts...)
+}
+
+// FitMedoidsContext returns a trained k-medoids model as FitMedoids
+// does, but stops training once the context is canceled. If canceled,
+// the model with the least cost trained so far is returned with the
+// context's error.
+func FitMedoidsContext(ctx context.Context, k int, data []Point, opts ...Option) (Medoids, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := validate(k, data); err != nil {
+		return nil, err
+	}
+
+	switch cfg.MedoidMthd {
+	case CLARA, CLARANS:
+		// Distances are computed as needed rather than cached
+		return newMedoids(ctx, k, data, cfg, func(i, j int) float64 { return cfg.Metric.Dist(data[i], data[j]) })
+	default:
+		dists := newTriMatrix(len(data))
+		dists.update(cfg.Metric, data)
+		return newMedoids(ctx, k, data, cfg, dists.dist)
+	}
+}
+
+// newMedoids returns the k-medoids model with the least cost over the
+// configured number of training rounds, given the distance between
+// each pair of data points. Each round draws from its own random
+// source seeded by the configured source. PAM is trained only once,
+// since every round would build and swap the same medoids.
+func newMedoids(ctx context.Context, k int, data []Point, cfg Config, dist func(i, j int) float64) (Medoids, error) {
+	rounds := cfg.TrainRounds
+	if cfg.MedoidMthd == PAM {
+		rounds = 1
+	}
+
+	var (
+		seeds   = make([]int64, rounds)
+		med     = make(Medoids, k)
+		minMed  = make(Medoids, k)
+		minCost = math.Inf(1)
+	)
+
+	for i := 0; i < len(seeds); i++ {
+		seeds[i] = cfg.Rand.Int63()
+	}
+
+	for round := 0; round < rounds; round++ {
+		if ctx.Err() != nil && round != 0 {
+			break
+		}
+
+		cfg.Rand = rand.New(rand.NewSource(seeds[round]))
+		switch cfg.MedoidMthd {
+		case PAM:
+			med.build(len(data), dist)
+		case CLARA:
+			// Each sample is built as it is drawn
+		default:
+			med.init(cfg, data)
+		}
+
+		if cost := med.train(ctx, cfg, data, dist); cost < minCost {
+			copy(minMed, med)
+			minCost = cost
+		}
+	}
+
+	return minMed, ctx.Err()
+}
+
+// init initializes medoids by the configured initialization method.
+// Each mean initialized is replaced by the index of the nearest data
+// point not already taken.
+func (med Medoids) init(cfg Config, data []Point) {
+	var (
+		mdl       = make(Model, 0, len(med))
+		meanDists = newTriMatrix(len(med))
+		taken     = make([]bool, len(data))
+	)
+
+	for i := 0; i < len(med); i++ {
+		mdl = append(mdl, make(Point, len(data[0])))
+	}
+
+	mdl.init(cfg, meanDists, data)
+	for i := 0; i < len(mdl); i++ {
+		var (
+			minJ    = -1
+			minDist float64
+		)
+
+		for j := 0; j < len(data); j++ {
+			if taken[j] {
+				continue
+			}
+
+			if dist := cfg.Metric.Dist(mdl[i], data[j]); minJ < 0 || dist < minDist {
+				minJ = j
+				minDist = dist
+			}
+		}
+
+		med[i] = minJ
+		taken[minJ] = true
+	}
+}
+
+// build initializes medoids greedily on n data points, given the
+// distance between each pair. The first medoid has the least total
+// distance to every other data point and each remaining medoid most
+// reduces the total distance from each data point to its nearest
+// medoid.
+func (med Medoids) build(n int, dist func(i, j int) float64) {
+	var (
+		nearDists = make([]float64, n)
+		taken     = make([]bool, n)
+		minCost   = math.Inf(1)
+	)
+
+	for i := 0; i < n; i++ {
+		var cost float64
+		for j := 0; j < n; j++ {
+			cost += dist(i, j)
+		}
+
+		if cost < minCost {
+			med[0] = i
+			minCost = cost
+		}
+	}
+
+	taken[med[0]] = true
+	for j := 0; j < n; j++ {
+		nearDists[j] = dist(j, med[0])
+	}
+
+	for c := 1; c < len(med); c++ {
+		var (
+			maxI    = -1
+			maxGain float64
+		)
+
+		for i := 0; i < n; i++ {
+			if taken[i] {
+				continue
+			}
+
+			var gain float64
+			for j := 0; j < n; j++ {
+				if d := dist(i, j); d < nearDists[j] {
+					gain += nearDists[j] - d
+				}
+			}
+
+			if maxI < 0 || maxGain < gain {
+				maxI = i
+				maxGain = gain
+			}
+		}
+
+		med[c] = maxI
+		taken[maxI] = true
+		for j := 0; j < n; j++ {
+			nearDists[j] = math.Min(nearDists[j], dist(j, maxI))
+		}
+	}
+}
+
+// train updates the medoids by the configured medoid method on a data
+// set, given the distance between each pair of data points, and
+// returns the cost.
+func (med Medoids) train(ctx context.Context, cfg Config, data []Point, dist func(i, j int) float64) float64 {
+	n := len(data)
+	switch cfg.MedoidMthd {
+	case Alternate:
+		return med.trainAlternate(ctx, cfg, n, dist)
+	case PAM:
+		return med.trainSwap(ctx, cfg, n, dist, false)
+	case FasterPAM:
+		return med.trainSwap(ctx, cfg, n, dist, true)
+	case CLARA:
+		return med.trainCLARA(ctx, cfg, data)
+	case CLARANS:
+		return med.trainCLARANS(ctx, cfg, n, dist)
+	default:
+		panic(ErrMedoidMthd)
+	}
+}
+
+// trainAlternate updates the medoids by alternating between assigning
+// each data point to its nearest medoid and choosing the data point in
+// each cluster with the least total distance to the rest of the
+// cluster. The cost is returned.
+func (med Medoids) trainAlternate(ctx context.Context, cfg Config, n int, dist func(i, j int) float64) float64 {
+	var (
+		near     = make([]int, n)
+		nearDist = make([]float64, n)
+		clusters = make([][]int, len(med))
+	)
+
+	for iter := 0; cfg.MaxIters == 0 || iter < cfg.MaxIters; iter++ {
+		med.assign(n, dist, near, nearDist, nil)
+		for c := 0; c < len(clusters); c++ {
+			clusters[c] = clusters[c][:0]
+		}
+
+		for j := 0; j < n; j++ {
+			clusters[near[j]] = append(clusters[near[j]], j)
+		}
+
+		var changed bool
+		for c := 0; c < len(clusters); c++ {
+			var (
+				minI    = med[c]
+				minCost float64
+			)
+
+			for _, j := range clusters[c] {
+				minCost += dist(minI, j)
+			}
+
+			for _, i := range clusters[c] {
+				var cost float64
+				for _, j := range clusters[c] {
+					cost += dist(i, j)
+				}
+
+				if cost < minCost {
+					minI = i
+					minCost = cost
+				}
+			}
+
+			if minI != med[c] {
+				med[c] = minI
+				changed = true
+			}
+		}
+
+		if !changed || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return med.assign(n, dist, near, nearDist, nil)
+}
+
+// trainSwap updates the medoids by swapping a medoid with a non-medoid
+// while doing so reduces the total distance from each data point to
+// its nearest medoid. If eager, each such swap is applied as it is
+// found. Otherwise, the best swap over every non-medoid is applied.
+// The cost is returned.
+func (med Medoids) trainSwap(ctx context.Context, cfg Config, n int, dist func(i, j int) float64, eager bool) float64 {
+	var (
+		near       = make([]int, n)
+		nearDist   = make([]float64, n)
+		secondDist = make([]float64, n)
+		loss       = make([]float64, len(med))
+		deltas     = make([]float64, len(med))
+		isMedoid   = make([]bool, n)
+		cost       = med.assign(n, dist, near, nearDist, secondDist)
+		swaps      int
+	)
+
+	for c := 0; c < len(med); c++ {
+		isMedoid[med[c]] = true
+	}
+
+	removalLoss(loss, near, nearDist, secondDist)
+	swap := func(c, i int) bool {
+		isMedoid[med[c]], isMedoid[i] = false, true
+		med[c] = i
+		cost = med.assign(n, dist, near, nearDist, secondDist)
+		removalLoss(loss, near, nearDist, secondDist)
+		swaps++
+		return (cfg.MaxIters == 0 || swaps < cfg.MaxIters) && ctx.Err() == nil
+	}
+
+	if eager {
+		// Visit the data points cyclically until every point has been
+		// visited since the last swap
+		for i, visits := 0, 0; visits < n; i, visits = (i+1)%n, visits+1 {
+			if isMedoid[i] {
+				continue
+			}
+
+			if delta, c := swapDelta(i, n, dist, loss, deltas, near, nearDist, secondDist); delta < 0 {
+				if !swap(c, i) {
+					break
+				}
+
+				visits = 0
+			}
+		}
+
+		return cost
+	}
+
+	for {
+		var (
+			minDelta   float64
+			minC, minI = -1, -1
+		)
+
+		for i := 0; i < n; i++ {
+			if isMedoid[i] {
+				continue
+			}
+
+			if delta, c := swapDelta(i, n, dist, loss, deltas, near, nearDist, secondDist); delta < minDelta {
+				minDelta, minC, minI = delta, c, i
+			}
+		}
+
+		if minI < 0 || !swap(minC, minI) {
+			return cost
+		}
+	}
+}
+
+// assign records the nearest medoid of each of n data points and the
+// distance to it, and the distance to the second nearest medoid if
+// secondDist is not nil. The cost is returned. That is, the total
+// distance from each data point to its nearest medoid.
+func (med Medoids) assign(n int, dist func(i, j int) float64, near []int, nearDist, secondDist []float64) float64 {
+	var cost float64
+	for j := 0; j < n; j++ {
+		near[j], nearDist[j] = 0, math.Inf(1)
+		second := math.Inf(1)
+		for c := 0; c < len(med); c++ {
+			switch d := dist(j, med[c]); {
+			case d < nearDist[j]:
+				near[j], nearDist[j], second = c, d, nearDist[j]
+			case d < second:
+				second = d
+			}
+		}
+
+		if secondDist != nil {
+			secondDist[j] = second
+		}
+
+		cost += nearDist[j]
+	}
+
+	return cost
+}
+
+// removalLoss records the increase in cost from removing each medoid.
+// That is, the total distance each data point nearest to the medoid
+// would move to its second nearest medoid.
+func removalLoss(loss []float64, near []int, nearDist, secondDist []float64) {
+	for c := 0; c < len(loss); c++ {
+		loss[c] = 0
+	}
+
+	for j := 0; j < len(near); j++ {
+		loss[near[j]] += secondDist[j] - nearDist[j]
+	}
+}
+
+// swapDelta returns the least change in cost from swapping the ith of
+// n data points with a medoid and the medoid to swap it with. The
+// deltas are overwritten.
+func swapDelta(i, n int, dist func(i, j int) float64, loss, deltas []float64, near []int, nearDist, secondDist []float64) (float64, int) {
+	if len(deltas) == 1 {
+		// Removing the only medoid leaves no second nearest medoid to
+		// fall back to, so every data point moves to i.
+		var delta float64
+		for j := 0; j < n; j++ {
+			delta += dist(i, j) - nearDist[j]
+		}
+
+		return delta, 0
+	}
+
+	var gain float64
+	copy(deltas, loss)
+	for j := 0; j < n; j++ {
+		switch d := dist(i, j); {
+		case d < nearDist[j]:
+			// j moves to i whichever medoid is removed
+			gain += d - nearDist[j]
+			deltas[near[j]] += nearDist[j] - secondDist[j]
+		case d < secondDist[j]:
+			// j moves to i only if its nearest medoid is removed
+			deltas[near[j]] += d - secondDist[j]
+		}
+	}
+
+	minC := 0
+	for c := 1; c < len(deltas); c++ {
+		if deltas[c] < deltas[minC] {
+			minC = c
+		}
+	}
+
+	return deltas[minC] + gain, minC
+}
+
+// Model returns a model of the medoids of a given data set. That is, a
+// copy of the data point at each index.
+func (med Medoids) Model(data []Point) Model {
+	mdl := make(Model, 0, len(med))
+	for i := 0; i < len(med); i++ {
+		mdl = append(mdl, data[med[i]].Copy())
+	}
+
+	return mdl
+}
+
+// Classes returns the classification of each data point of the data
+// set the medoids were trained upon by a given metric.
+func (med Medoids) Classes(metric Metric, data []Point) []int {
+	classes, _ := med.Model(data).classesCost(metric, data)
+	return classes
+}
+
+// Cost returns the total distance by a given metric from each data
+// point to its nearest medoid, given the data set the medoids were
+// trained upon.
+func (med Medoids) Cost(metric Metric, data []Point) float64 {
+	_, cost := med.Model(data).classesCost(metric, data)
+	return cost
+}
+
+// classesCost returns the classification of each data point by a given
+// metric and the total distance from each data point to its mean.
+func (mdl Model) classesCost(metric Metric, data []Point) ([]int, float64) {
+	var (
+		classes = make([]int, 0, len(data))
+		cost    float64
+	)
+
+	for i := 0; i < len(data); i++ {
+		class, dist := mdl.classDist(metric, data[i])
+		classes = append(classes, class)
+		cost += dist
+	}
+
+	return classes, cost
+}
@@ -0,0 +1,187 @@
+package kmeans
+
+import "math"
+
+// Metric measures the distance between two points. Training assigns
+// each data point to the mean nearest by the metric, but each mean is
+// still the average of the data points assigned to it. The inertia
+// minimized by training is the sum of the cost of the distance from
+// each data point to its mean.
+type Metric interface {
+	// Dist returns the distance between two points.
+	Dist(p, q Point) float64
+
+	// Cost returns the cost of a data point at a given distance from
+	// its mean, summed into the inertia and score of a model.
+	Cost(dist float64) float64
+
+	// Triangle determines if the metric satisfies the triangle
+	// inequality. If not, no distance calculations are skipped during
+	// training, and the Elkan and Hamerly training methods may not be
+	// used.
+	Triangle() bool
+}
+
+// Euclidean measures the Euclidean distance between points. This is
+// the default metric.
+type Euclidean struct{}
+
+// Dist returns the Euclidean distance between two points.
+func (Euclidean) Dist(p, q Point) float64 {
+	return p.Dist(q)
+}
+
+// Cost returns the squared distance.
+func (Euclidean) Cost(dist float64) float64 {
+	return dist * dist
+}
+
+// Triangle returns true.
+func (Euclidean) Triangle() bool {
+	return true
+}
+
+// SqEuclidean measures the squared Euclidean distance between points.
+type SqEuclidean struct{}
+
+// Dist returns the squared Euclidean distance between two points.
+func (SqEuclidean) Dist(p, q Point) float64 {
+	return p.SqDist(q)
+}
+
+// Cost returns the distance, which is already squared.
+func (SqEuclidean) Cost(dist float64) float64 {
+	return dist
+}
+
+// Triangle returns false.
+func (SqEuclidean) Triangle() bool {
+	return false
+}
+
+// Manhattan measures the Manhattan (taxicab or L1) distance between
+// points.
+type Manhattan struct{}
+
+// Dist returns the sum of the absolute differences of each dimension
+// of two points.
+func (Manhattan) Dist(p, q Point) float64 {
+	if len(p) != len(q) {
+		panic(ErrDims)
+	}
+
+	var d float64 // d = sum(|pi-qi|)
+	for i := 0; i < len(p); i++ {
+		d += math.Abs(p[i] - q[i])
+	}
+
+	return d
+}
+
+// Cost returns the distance.
+func (Manhattan) Cost(dist float64) float64 {
+	return dist
+}
+
+// Triangle returns true.
+func (Manhattan) Triangle() bool {
+	return true
+}
+
+// Chebyshev measures the Chebyshev (chessboard or L∞) distance between
+// points.
+type Chebyshev struct{}
+
+// Dist returns the largest absolute difference of any dimension of
+// two points.
+func (Chebyshev) Dist(p, q Point) float64 {
+	if len(p) != len(q) {
+		panic(ErrDims)
+	}
+
+	var d float64 // d = max(|pi-qi|)
+	for i := 0; i < len(p); i++ {
+		d = math.Max(d, math.Abs(p[i]-q[i]))
+	}
+
+	return d
+}
+
+// Cost returns the squared distance.
+func (Chebyshev) Cost(dist float64) float64 {
+	return dist * dist
+}
+
+// Triangle returns true.
+func (Chebyshev) Triangle() bool {
+	return true
+}
+
+// Minkowski measures the Minkowski (Lp) distance of order P between
+// points. Orders one and two are the Manhattan and Euclidean
+// distances, respectively.
+type Minkowski struct {
+	P float64
+}
+
+// Dist returns the Minkowski distance of order P between two points.
+func (m Minkowski) Dist(p, q Point) float64 {
+	if len(p) != len(q) {
+		panic(ErrDims)
+	}
+
+	var d float64 // d = sum(|pi-qi|^p)^(1/p)
+	for i := 0; i < len(p); i++ {
+		d += math.Pow(math.Abs(p[i]-q[i]), m.P)
+	}
+
+	return math.Pow(d, 1.0/m.P)
+}
+
+// Cost returns the distance raised to the order, agreeing with the
+// Manhattan and Euclidean costs at orders one and two.
+func (m Minkowski) Cost(dist float64) float64 {
+	return math.Pow(dist, m.P)
+}
+
+// Triangle determines if the order is at least one.
+func (m Minkowski) Triangle() bool {
+	return 1 <= m.P
+}
+
+// Cosine measures the cosine distance between points. That is, one
+// minus the cosine of the angle between them. The origin is at
+// distance one from every point.
+type Cosine struct{}
+
+// Dist returns the cosine distance between two points.
+func (Cosine) Dist(p, q Point) float64 {
+	if len(p) != len(q) {
+		panic(ErrDims)
+	}
+
+	r := p.Mag() * q.Mag()
+	if r == 0 {
+		return 1
+	}
+
+	return 1 - p.Dot(q)/r
+}
+
+// Cost returns the distance. Minimizing the sum of cosine distances
+// maximizes the sum of cosine similarities, as spherical k-means does.
+func (Cosine) Cost(dist float64) float64 {
+	return dist
+}
+
+// Triangle returns false.
+func (Cosine) Triangle() bool {
+	return false
+}
+
+// sqDist returns the squared distance between two points by a given
+// metric.
+func sqDist(metric Metric, p, q Point) float64 {
+	d := metric.Dist(p, q)
+	return d * d
+}
@@ -0,0 +1,73 @@
+package kmeans
+
+// --------------------------------------------------------------------
+//    Mini-batch k-means (Sculley, 2010)
+// --------------------------------------------------------------------
+// 1. Sample a batch of b data points uniformly with replacement.
+// 2. Assign each point in the batch to its nearest mean.
+// 3. For each point x in the batch assigned to mean c, increment the
+//    count v(c) of points c has seen and move c toward x by the
+//    per-mean learning rate 1/v(c). That is, c = (1-1/v(c))c + x/v(c).
+// 4. Stop after a fixed number of batches or once no mean moves
+//    farther than a given tolerance, otherwise go to step 1.
+// --------------------------------------------------------------------
+//  * Web-scale k-means clustering.
+//    https://www.eecs.tufts.edu/~dsculley/papers/fastkmeans.pdf
+// --------------------------------------------------------------------
+
+// trainMiniBatch updates the means using batches sampled from the
+// given data set. The mean distance lookup table is kept up to date.
+// Training stops with MaxIters once every batch is used, or as the
+// monitor otherwise determines.
+func (mdl Model) trainMiniBatch(mon *monitor, meanDists triMatrix, data []Point) StopReason {
+	var (
+		counts   = make([]float64, len(mdl))
+		batch    = make([]Point, mon.cfg.BatchSize)
+		batchCls = make(classes, mon.cfg.BatchSize)
+	)
+
+	for iter := 0; iter < mon.cfg.BatchIters; iter++ {
+		var inertia float64
+		for i := 0; i < len(batch); i++ {
+			var dist float64
+			batch[i] = data[mon.cfg.Rand.Intn(len(data))]
+			batchCls[i], dist = mdl.classDistMem(mon.cfg.Metric, batch[i], meanDists)
+			inertia += mon.cfg.Metric.Cost(dist)
+		}
+
+		mon.before(mdl)
+		for i := 0; i < len(batch); i++ {
+			class := batchCls[i]
+			counts[class]++
+
+			rate := 1.0 / counts[class]
+			for j := 0; j < len(mdl[class]); j++ {
+				mdl[class][j] += rate * (batch[i][j] - mdl[class][j])
+			}
+		}
+
+		if mon.cfg.Spherical {
+			Normalize(mdl...)
+		}
+
+		meanDists.update(mon.cfg.Metric, mdl)
+		if rsn := mon.batched(mdl, inertia); rsn != 0 {
+			return rsn
+		}
+	}
+
+	return MaxIters
+}
+
+// maxDrift returns the largest distance any mean has moved from the
+// corresponding mean in a previous model.
+func (mdl Model) maxDrift(prevMdl Model) float64 {
+	var maxDist float64
+	for i := 0; i < len(mdl); i++ {
+		if dist := mdl[i].Dist(prevMdl[i]); maxDist < dist {
+			maxDist = dist
+		}
+	}
+
+	return maxDist
+}
@@ -1,10 +1,11 @@
 package kmeans
 
 import (
-	"math"
-	"math/rand"
+	"context"
+	"fmt"
 	"sort"
 	"strings"
+	"sync"
 )
 
 // --------------------------------------------------------------------
@@ -46,54 +47,182 @@ import (
 type Model []Point
 
 // New returns a trained model. By default, the model initialized with
-// random points from the data set and is trained once.
+// random points from the data set and is trained once. New panics if
+// the model cannot be trained; see Fit.
 func New(k int, data []Point, opts ...Option) Model {
-	if len(data) < k {
-		panic(errDataSize)
+	mdl, err := Fit(k, data, opts...)
+	if err != nil {
+		panic(err)
 	}
 
+	return mdl
+}
+
+// Fit returns a trained model as New does, but returns an error if
+// the model cannot be trained. Any error returned matches one of the
+// exported error values by errors.Is.
+func Fit(k int, data []Point, opts ...Option) (Model, error) {
+	return FitContext(context.Background(), k, data, opts...)
+}
+
+// FitContext returns a trained model as Fit does, but stops training
+// once the context is canceled. The context is checked between each
+// iteration and training round. If canceled, the highest scoring model
+// trained so far is returned with the context's error.
+func FitContext(ctx context.Context, k int, data []Point, opts ...Option) (Model, error) {
+	mdl, _, err := fit(ctx, k, data, false, opts...)
+	return mdl, err
+}
+
+// FitReport returns a trained model as FitContext does, along with a
+// report describing each training round.
+func FitReport(ctx context.Context, k int, data []Point, opts ...Option) (Model, TrainReport, error) {
+	return fit(ctx, k, data, true, opts...)
+}
+
+// fit validates the options and data and returns a trained model and a
+// report describing each training round. Each iteration is recorded in
+// the report only if record is set.
+func fit(ctx context.Context, k int, data []Point, record bool, opts ...Option) (Model, TrainReport, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.Validate(); err != nil {
+		return nil, TrainReport{}, err
+	}
+
+	if err := validate(k, data); err != nil {
+		return nil, TrainReport{}, err
+	}
+
+	return newModel(ctx, k, data, cfg, record)
+}
+
+// validate determines if a model with k means may be trained on a
+// given data set.
+func validate(k int, data []Point) error {
+	switch {
+	case k < 1:
+		return fmt.Errorf("%w: %d", ErrK, k)
+	case len(data) < k:
+		return fmt.Errorf("%w: %d points for %d clusters", ErrDataSize, len(data), k)
+	case len(data[0]) == 0:
+		return fmt.Errorf("%w: no dimensions", ErrDims)
+	}
+
+	if err := Validate(data...); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(data); i++ {
+		if !data[i].finite() {
+			return fmt.Errorf("%w: point %d", ErrNonFinite, i)
+		}
+	}
+
+	return nil
+}
+
+// newModel returns a model trained on a given data set by a given
+// configuration and a report describing each round. Both are assumed
+// to be valid. Rounds are trained concurrently by the configured
+// number of workers, each round drawing from its own random source
+// seeded by the configured source. If the context is canceled, the
+// highest scoring model so far is returned with the context's error.
+func newModel(ctx context.Context, k int, data []Point, cfg Config, record bool) (Model, TrainReport, error) {
 	var (
-		cfg       = NewConfig(opts...)
-		meanDists = newTriMatrix(k)
-		mdl       = make(Model, 0, k)
-		maxScrMdl = make(Model, 0, k)
-		cls       = make(classes, len(data))
-		maxScr    = -math.MaxFloat64
+		seeds   = make([]int64, cfg.TrainRounds)
+		rounds  = make([]RoundReport, cfg.TrainRounds)
+		trained = make([]bool, cfg.TrainRounds)
+		workers = make([]*roundWorker, 0, cfg.Workers)
+		jobs    = make(chan int)
+		wg      sync.WaitGroup
 	)
 
-	for i := 0; i < k; i++ {
-		mdl = append(mdl, make(Point, len(data[0])))
-		maxScrMdl = append(maxScrMdl, make(Point, len(data[0])))
+	for i := 0; i < len(seeds); i++ {
+		seeds[i] = cfg.Rand.Int63()
+	}
+
+	for i := 0; i < cfg.Workers && i < cfg.TrainRounds; i++ {
+		w := newRoundWorker(k, len(data[0]), len(data))
+		workers = append(workers, w)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := range jobs {
+				if ctx.Err() == nil || round == 0 {
+					rounds[round] = w.train(ctx, cfg, round, seeds[round], data, record)
+					trained[round] = true
+				}
+			}
+		}()
+	}
+
+	for round := 0; round < cfg.TrainRounds; round++ {
+		jobs <- round
 	}
 
-	for ; 0 < cfg.TrainRounds; cfg.TrainRounds-- {
-		mdl.init(cfg.Mthd, meanDists, data)
-		mdl.train(meanDists, cls, data)
-		if score := mdl.Score(data...); maxScr < score {
-			maxScrMdl.copyFrom(mdl)
-			maxScr = score
+	close(jobs)
+	wg.Wait()
+
+	maxScrWorker := workers[0]
+	for _, w := range workers[1:] {
+		if maxScrWorker.maxScr < w.maxScr || maxScrWorker.maxScr == w.maxScr && w.maxRound < maxScrWorker.maxRound {
+			maxScrWorker = w
 		}
 	}
 
-	return maxScrMdl
+	var (
+		rpt = TrainReport{Rounds: make([]RoundReport, 0, cfg.TrainRounds)}
+		err error
+	)
+
+	for round := 0; round < cfg.TrainRounds; round++ {
+		if round == maxScrWorker.maxRound {
+			rpt.Best = len(rpt.Rounds)
+		}
+
+		switch {
+		case !trained[round]:
+			err = ctx.Err()
+			continue
+		case rounds[round].Stop == Canceled:
+			err = ctx.Err()
+		case rounds[round].Stop == EmptyCluster && err == nil:
+			err = fmt.Errorf("%w: round %d", ErrEmptyCluster, round)
+		}
+
+		rpt.Rounds = append(rpt.Rounds, rounds[round])
+	}
+
+	if cfg.RepairMthd == RepairDrop {
+		return maxScrWorker.maxScrMdl.dropEmpty(cfg.Metric, data), rpt, err
+	}
+
+	return maxScrWorker.maxScrMdl, rpt, err
 }
 
 // Class returns the classification of a point.
 func (mdl Model) Class(datum Point) int {
-	class, _ := mdl.classDist(datum)
+	return mdl.ClassBy(Euclidean{}, datum)
+}
+
+// ClassBy returns the classification of a point by a given metric,
+// such as the metric the model was trained with.
+func (mdl Model) ClassBy(metric Metric, datum Point) int {
+	class, _ := mdl.classDist(metric, datum)
 	return class
 }
 
-// classDist returns the classification and distance between a data
-// point and its mean.
-func (mdl Model) classDist(datum Point) (int, float64) {
+// classDist returns the classification and distance by a given metric
+// between a data point and its mean.
+func (mdl Model) classDist(metric Metric, datum Point) (int, float64) {
 	var (
 		class     int
-		minSqDist = mdl[class].Dist(datum)
+		minSqDist = metric.Dist(mdl[class], datum)
 	)
 
 	for i := 1; i < len(mdl); i++ {
-		if dist := mdl[i].Dist(datum); dist < minSqDist {
+		if dist := metric.Dist(mdl[i], datum); dist < minSqDist {
 			class = i
 			minSqDist = dist
 		}
@@ -102,10 +231,11 @@ func (mdl Model) classDist(datum Point) (int, float64) {
 	return class, minSqDist
 }
 
-// classDistMem returns the classification and distance between a data
-// point and its mean. Requires the distance between each mean be
-// computed in advance, but otherwise behaves the same as classDist.
-func (mdl Model) classDistMem(datum Point, meanDists triMatrix) (int, float64) {
+// classDistMem returns the classification and distance by a given
+// metric between a data point and its mean. Requires the distance
+// between each mean be computed in advance, but otherwise behaves the
+// same as classDist.
+func (mdl Model) classDistMem(metric Metric, datum Point, meanDists triMatrix) (int, float64) {
 	// Implements triangle inequality to prevent needless distance
 	// calculations. If point p is assigned to cluster 0 with mean m0
 	// and we want to know if cluster mean m1 is nearer, there's no
@@ -113,16 +243,22 @@ func (mdl Model) classDistMem(datum Point, meanDists triMatrix) (int, float64) {
 	// from p to m0 is less than half the distance from m0 to m1. That
 	// is, if d(p, m0) <= d(m0, m1) / 2, then don't compute d(p, m1).
 
-	// Note: SqDist fails here, so Dist must be used.
+	// Note: SqDist fails here, so Dist must be used. Likewise, metrics
+	// not satisfying the triangle inequality must compute every
+	// distance.
+
+	if !metric.Triangle() {
+		return mdl.classDist(metric, datum)
+	}
 
 	var (
 		class   int
-		minDist = mdl[class].Dist(datum)
+		minDist = metric.Dist(mdl[class], datum)
 	)
 
 	for i := 1; i < len(mdl); i++ {
 		if meanDists.dist(class, i)/2.0 < minDist {
-			if dist := mdl[i].Dist(datum); dist < minDist {
+			if dist := metric.Dist(mdl[i], datum); dist < minDist {
 				class = i
 				minDist = dist
 			}
@@ -134,14 +270,20 @@ func (mdl Model) classDistMem(datum Point, meanDists triMatrix) (int, float64) {
 
 // Classes returns the classification of each data point.
 func (mdl Model) Classes(data ...Point) []int {
+	return mdl.ClassesBy(Euclidean{}, data...)
+}
+
+// ClassesBy returns the classification of each data point by a given
+// metric.
+func (mdl Model) ClassesBy(metric Metric, data ...Point) []int {
 	var (
 		classes   = make([]int, 0, len(data))
 		meanDists = newTriMatrix(len(mdl))
 	)
 
-	meanDists.update(mdl)
+	meanDists.update(metric, mdl)
 	for i := 0; i < len(data); i++ {
-		class, _ := mdl.classDistMem(data[i], meanDists)
+		class, _ := mdl.classDistMem(metric, data[i], meanDists)
 		classes = append(classes, class)
 	}
 
@@ -150,14 +292,20 @@ func (mdl Model) Classes(data ...Point) []int {
 
 // Cluster returns the data that is classified in the given class.
 func (mdl Model) Cluster(class int, data ...Point) []Point {
+	return mdl.ClusterBy(Euclidean{}, class, data...)
+}
+
+// ClusterBy returns the data that is classified in the given class by
+// a given metric.
+func (mdl Model) ClusterBy(metric Metric, class int, data ...Point) []Point {
 	var (
 		cluster   = make([]Point, 0, len(data))
 		meanDists = newTriMatrix(len(mdl))
 	)
 
-	meanDists.update(mdl)
+	meanDists.update(metric, mdl)
 	for i := 0; i < len(data); i++ {
-		if c, _ := mdl.classDistMem(data[i], meanDists); c == class {
+		if c, _ := mdl.classDistMem(metric, data[i], meanDists); c == class {
 			cluster = append(cluster, data[i].Copy())
 		}
 	}
@@ -167,8 +315,14 @@ func (mdl Model) Cluster(class int, data ...Point) []Point {
 
 // Clusters returns the data classified into k clusters.
 func (mdl Model) Clusters(data ...Point) [][]Point {
+	return mdl.ClustersBy(Euclidean{}, data...)
+}
+
+// ClustersBy returns the data classified into k clusters by a given
+// metric.
+func (mdl Model) ClustersBy(metric Metric, data ...Point) [][]Point {
 	var (
-		classes = mdl.Classes(data...)
+		classes = mdl.ClassesBy(metric, data...)
 		sizes   = make([]int, len(mdl))
 	)
 
@@ -214,10 +368,16 @@ func (mdl Model) Dist(class int, datum Point) float64 {
 // set of data that is classified as the given class and the number of
 // points in the subset that were assigned to the given class.
 func (mdl Model) Err(class int, data ...Point) float64 {
-	var err float64 // e = sum((xi-m)^2, i = 0, 1, 2,...)
+	return mdl.ErrBy(Euclidean{}, class, data...)
+}
+
+// ErrBy returns the sum of the cost by a given metric of the distance
+// from each data point classified in the given class to its mean.
+func (mdl Model) ErrBy(metric Metric, class int, data ...Point) float64 {
+	var err float64 // e = sum(cost(d(xi, m)), i = 0, 1, 2,...)
 	for i := 0; i < len(data); i++ {
-		if c, d := mdl.classDist(data[i]); c == class {
-			err += d * d
+		if c, d := mdl.classDist(metric, data[i]); c == class {
+			err += metric.Cost(d)
 		}
 	}
 
@@ -227,19 +387,26 @@ func (mdl Model) Err(class int, data ...Point) float64 {
 // Errs classifies a given set of data and returns the variance for
 // each cluster.
 func (mdl Model) Errs(data ...Point) []float64 {
+	return mdl.ErrsBy(Euclidean{}, data...)
+}
+
+// ErrsBy classifies a given set of data by a given metric and returns
+// the sum of the cost of the distance from each data point to its mean
+// for each cluster.
+func (mdl Model) ErrsBy(metric Metric, data ...Point) []float64 {
 	errs := make([]float64, len(mdl))
 	for i := 0; i < len(data); i++ {
-		class, dist := mdl.classDist(data[i])
-		errs[class] += dist * dist
+		class, dist := mdl.classDist(metric, data[i])
+		errs[class] += metric.Cost(dist)
 	}
 
 	return errs
 }
 
-// init initializes a model by a given method. The mean distances may
-// be updated as the method requires.
-func (mdl Model) init(mthd InitMethod, meanDists triMatrix, data []Point) {
-	switch mthd {
+// init initializes a model by the configured method. The mean
+// distances may be updated as the method requires.
+func (mdl Model) init(cfg Config, meanDists triMatrix, data []Point) {
+	switch cfg.Mthd {
 	case Random:
 		var (
 			partSize = len(data) / len(mdl)
@@ -247,17 +414,20 @@ func (mdl Model) init(mthd InitMethod, meanDists triMatrix, data []Point) {
 		)
 
 		for i < len(mdl)-1 {
-			copy(mdl[i], data[rand.Intn(partSize)+j])
+			copy(mdl[i], data[cfg.Rand.Intn(partSize)+j])
 			i++
 			j += partSize
 		}
 
-		copy(mdl[i], data[rand.Intn(len(data)-j)+j])
-		meanDists.update(mdl)
+		copy(mdl[i], data[cfg.Rand.Intn(len(data)-j)+j])
+		meanDists.update(cfg.Metric, mdl)
 	case PlusPlus:
-		copy(mdl[0], data[rand.Intn(len(data))])
-		meanDists.update(mdl)
+		copy(mdl[0], data[cfg.Rand.Intn(len(data))])
+		meanDists.update(cfg.Metric, mdl)
 
+		// Only the means chosen so far are measured. The remaining means
+		// may hold a previous round's means, since workers reuse their
+		// models, and would otherwise bias the traversal.
 		for i := 1; i < len(mdl); i++ {
 			var (
 				maxJ    int
@@ -265,20 +435,36 @@ func (mdl Model) init(mthd InitMethod, meanDists triMatrix, data []Point) {
 			)
 
 			for j := 0; j < len(data); j++ {
-				if _, dist := mdl.classDistMem(data[j], meanDists); maxDist < dist {
+				if _, dist := mdl[:i].classDistMem(cfg.Metric, data[j], meanDists); maxDist < dist {
 					maxJ = j
 					maxDist = dist
 				}
 			}
 
 			copy(mdl[i], data[maxJ])
-			meanDists.update(mdl)
+			meanDists.update(cfg.Metric, mdl)
 		}
 	case FirstK:
 		mdl.copyFrom(data[:len(mdl)])
-		meanDists.update(data)
+		meanDists.update(cfg.Metric, mdl)
+	case D2:
+		mdl.initD2(cfg.Rand, cfg.Metric, 1, meanDists, data, nil)
+	case GreedyD2:
+		trials := cfg.Trials
+		if trials <= 0 {
+			trials = greedyTrials(len(mdl))
+		}
+
+		mdl.initD2(cfg.Rand, cfg.Metric, trials, meanDists, data, nil)
+	case Scalable:
+		oversampling := cfg.Oversampling
+		if oversampling <= 0 {
+			oversampling = 2 * float64(len(mdl))
+		}
+
+		mdl.initScalable(cfg, oversampling, meanDists, data)
 	default:
-		panic(errInitMthd)
+		panic(ErrInitMthd)
 	}
 }
 
@@ -305,20 +491,40 @@ func (mdl Model) Means() []Point {
 // Score indicates how well a model clusters data. A higher score
 // inidicates the model is a better fit.
 func (mdl Model) Score(data ...Point) float64 {
-	var sumSqDists float64
+	return mdl.score(Euclidean{}, data)
+}
+
+// ScoreBy indicates how well a model clusters data by a given metric.
+// That is, the negative sum of the cost by the metric of the distance
+// from each data point to its mean, as training rounds are compared.
+func (mdl Model) ScoreBy(metric Metric, data ...Point) float64 {
+	return mdl.score(metric, data)
+}
+
+// score indicates how well a model clusters data, measuring distances
+// by a given metric. That is, the negative inertia, or sum of the cost
+// by the metric of the distance from each data point to its mean.
+func (mdl Model) score(metric Metric, data []Point) float64 {
+	var cost float64
 	for i := 0; i < len(data); i++ {
-		_, dist := mdl.classDist(data[i])
-		sumSqDists -= dist * dist
+		_, dist := mdl.classDist(metric, data[i])
+		cost -= metric.Cost(dist)
 	}
 
-	return sumSqDists
+	return cost
 }
 
 // Size returns the size of the specified cluster.
 func (mdl Model) Size(class int, data ...Point) int {
+	return mdl.SizeBy(Euclidean{}, class, data...)
+}
+
+// SizeBy returns the size of the specified cluster classified by a
+// given metric.
+func (mdl Model) SizeBy(metric Metric, class int, data ...Point) int {
 	var size int
 	for i := 0; i < len(data); i++ {
-		if c, _ := mdl.classDist(data[i]); c == class {
+		if c, _ := mdl.classDist(metric, data[i]); c == class {
 			size++
 		}
 	}
@@ -328,9 +534,15 @@ func (mdl Model) Size(class int, data ...Point) int {
 
 // Sizes returns the sizes of each cluster.
 func (mdl Model) Sizes(data ...Point) []int {
+	return mdl.SizesBy(Euclidean{}, data...)
+}
+
+// SizesBy returns the sizes of each cluster classified by a given
+// metric.
+func (mdl Model) SizesBy(metric Metric, data ...Point) []int {
 	sizes := make([]int, len(mdl))
 	for i := 0; i < len(data); i++ {
-		class, _ := mdl.classDist(data[i])
+		class, _ := mdl.classDist(metric, data[i])
 		sizes[class]++
 	}
 
@@ -366,54 +578,203 @@ func (mdl Model) String() string {
 
 // Train updates the means using the given data set.
 func (mdl Model) Train(data ...Point) {
+	mdl.TrainWith(data)
+}
+
+// TrainWith updates the means using the given data set and the
+// training options provided, returning the criterion that stopped
+// training. Initialization options are ignored. TrainWith panics if
+// the model cannot be trained on the data; see TrainContext.
+func (mdl Model) TrainWith(data []Point, opts ...Option) StopReason {
+	rsn, err := mdl.TrainContext(context.Background(), data, opts...)
+	if err != nil && rsn != EmptyCluster {
+		panic(err)
+	}
+
+	return rsn
+}
+
+// TrainContext updates the means as TrainWith does, but stops training
+// once the context is canceled. The context is checked between each
+// iteration. If canceled, the means are left as they were after the
+// last iteration and the context's error is returned. Since the model
+// is updated in place, empty clusters are kept rather than dropped.
+// If a cluster becomes empty and the repair method is RepairFail,
+// ErrEmptyCluster is returned. The options, means, and data are
+// validated as Fit does, and if invalid, the model is not trained and
+// an error is returned with no stop reason. Training on no data leaves
+// the model as it is and returns Converged.
+func (mdl Model) TrainContext(ctx context.Context, data []Point, opts ...Option) (StopReason, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.Validate(); err != nil {
+		return 0, err
+	}
+
+	if len(data) == 0 {
+		return Converged, nil
+	}
+
+	if err := mdl.validate(data); err != nil {
+		return 0, err
+	}
+
 	var (
 		meanDists = newTriMatrix(len(mdl))
 		cls       = make(classes, len(data))
 	)
 
-	meanDists.update(mdl)
-	mdl.train(meanDists, cls, data)
+	meanDists.update(cfg.Metric, mdl)
+	switch rsn := mdl.train(newMonitor(ctx, cfg, 0, mdl, nil), meanDists, cls, data); rsn {
+	case Canceled:
+		return rsn, ctx.Err()
+	case EmptyCluster:
+		return rsn, ErrEmptyCluster
+	default:
+		return rsn, nil
+	}
+}
+
+// validate determines if a model may be trained on a given data set.
+// The means and data points must share their dimensions and be finite.
+func (mdl Model) validate(data []Point) error {
+	switch {
+	case len(mdl) == 0:
+		return fmt.Errorf("%w: no means", ErrK)
+	case len(mdl[0]) == 0:
+		return fmt.Errorf("%w: no dimensions", ErrDims)
+	}
+
+	for i := 0; i < len(mdl); i++ {
+		switch {
+		case len(mdl[i]) != len(mdl[0]):
+			return fmt.Errorf("%w: mean %d has %d dimensions, expected %d", ErrDims, i, len(mdl[i]), len(mdl[0]))
+		case !mdl[i].finite():
+			return fmt.Errorf("%w: mean %d", ErrNonFinite, i)
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		switch {
+		case len(data[i]) != len(mdl[0]):
+			return fmt.Errorf("%w: point %d has %d dimensions, expected %d", ErrDims, i, len(data[i]), len(mdl[0]))
+		case !data[i].finite():
+			return fmt.Errorf("%w: point %d", ErrNonFinite, i)
+		}
+	}
+
+	return nil
+}
+
+// train updates the means by the configured method using the given
+// data set and mean distance lookup table, stopping as the monitor
+// determines. If training is spherical, the means are normalized
+// first. The criterion that stopped training is returned.
+func (mdl Model) train(mon *monitor, meanDists triMatrix, cls classes, data []Point) StopReason {
+	var rsn StopReason
+	if mon.cfg.Spherical {
+		Normalize(mdl...)
+		meanDists.update(mon.cfg.Metric, mdl)
+	}
+
+	mon.started(mdl)
+	switch mon.cfg.TrainMthd {
+	case Lloyd:
+		rsn = mdl.trainLloyd(mon, meanDists, cls, data)
+	case Elkan:
+		rsn = mdl.trainElkan(mon, meanDists, cls, data)
+	case Hamerly:
+		rsn = mdl.trainHamerly(mon, meanDists, cls, data)
+	case MiniBatch:
+		rsn = mdl.trainMiniBatch(mon, meanDists, data)
+	default:
+		panic(ErrTrainMthd)
+	}
+
+	mon.done(mdl, data, rsn)
+	return rsn
 }
 
-// train updates the means using the given data set and mean distance
-// lookup table.
-func (mdl Model) train(meanDists triMatrix, cls classes, data []Point) {
-	for i := 0; ; i++ {
-		if !cls.update(mdl, meanDists, data) {
-			return
+// trainLloyd updates the means using the given data set and mean
+// distance lookup table, stopping as the monitor determines.
+func (mdl Model) trainLloyd(mon *monitor, meanDists triMatrix, cls classes, data []Point) StopReason {
+	acc := newAccumulator(mon.cfg, len(mdl), len(mdl[0]), len(data))
+	for {
+		changes := cls.update(mon.cfg, mdl, meanDists, data)
+		if mon.assigned(mdl, changes); changes == 0 {
+			return Converged
 		}
 
-		mdl.update(cls, data)
-		meanDists.update(mdl)
+		mon.before(mdl)
+		repairs := acc.update(mdl, cls, data)
+		meanDists.update(mon.cfg.Metric, mdl)
+		if rsn := mon.after(mdl, data, repairs); rsn != 0 {
+			return rsn
+		}
 	}
 }
 
 // update the model with data points as new means that have the
-// smallest variance in their respective class.
-func (mdl Model) update(cls classes, data []Point) {
+// smallest variance in their respective class. Each mean is reduced
+// in chunk order from partial sums over chunks of the data, which are
+// shared among the configured number of step workers. Empty classes
+// are repaired by the configured repair method. If training is
+// spherical, each mean is then normalized. If configured for
+// k-medians, each mean is instead updated to the coordinate-wise
+// median of its class. The number of empty classes is returned.
+func (mdl Model) update(cfg Config, cls classes, data []Point) int {
 	if len(cls) != len(data) {
-		panic(errDims)
+		panic(ErrDims)
 	}
 
-	for i := 0; i < len(mdl); i++ {
-		for j := 0; j < len(mdl[i]); j++ {
-			mdl[i][j] = 0.0
+	if cfg.Medians {
+		return mdl.repair(cfg, mdl.medians(cls, data, nil), cls, data)
+	}
+
+	var (
+		sums  = make([]Model, chunks(len(data)))
+		sizes = make([][]float64, chunks(len(data)))
+	)
+
+	forChunks(cfg.StepWorkers, len(data), func(chunk, lo, hi int) {
+		sums[chunk] = make(Model, 0, len(mdl))
+		for i := 0; i < len(mdl); i++ {
+			sums[chunk] = append(sums[chunk], make(Point, len(mdl[i])))
+		}
+
+		sizes[chunk] = make([]float64, len(mdl))
+		for j := lo; j < hi; j++ {
+			sums[chunk][cls[j]].Add(data[j])
+			sizes[chunk][cls[j]]++
 		}
+	})
 
+	var empties []int
+	for i := 0; i < len(mdl); i++ {
 		var size float64
-		for j := 0; j < len(data); j++ {
-			if i == cls[j] {
-				mdl[i].Add(data[j])
-				size++
-			}
+		for chunk := 0; chunk < len(sizes); chunk++ {
+			size += sizes[chunk][i]
 		}
 
 		if size == 0 {
-			// Cluster is empty; randomly select a representative
-			mdl[i].Add(data[rand.Intn(len(data))])
+			empties = append(empties, i)
 			continue
 		}
 
+		for j := 0; j < len(mdl[i]); j++ {
+			mdl[i][j] = 0.0
+		}
+
+		for chunk := 0; chunk < len(sums); chunk++ {
+			mdl[i].Add(sums[chunk][i])
+		}
+
 		mdl[i].ScalMult(1.0 / size)
 	}
+
+	repairs := mdl.repair(cfg, empties, cls, data)
+	if cfg.Spherical {
+		Normalize(mdl...)
+	}
+
+	return repairs
 }
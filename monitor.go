@@ -0,0 +1,154 @@
+package kmeans
+
+import (
+	"context"
+	"math"
+)
+
+// monitor determines when a training round should stop, given the
+// means before and after each update. Each step is recorded in the
+// round report, if provided, and passed to the observer, if
+// configured.
+type monitor struct {
+	ctx     context.Context
+	cfg     Config
+	round   int
+	rpt     *RoundReport
+	iters   int
+	prevMdl Model
+	inertia float64
+}
+
+// newMonitor returns a monitor ready to observe a training round of a
+// given model. Training stops once the context is canceled. The round
+// report may be nil.
+func newMonitor(ctx context.Context, cfg Config, round int, mdl Model, rpt *RoundReport) *monitor {
+	mon := monitor{
+		ctx:     ctx,
+		cfg:     cfg,
+		round:   round,
+		rpt:     rpt,
+		prevMdl: mdl.Copy(),
+		inertia: math.Inf(1),
+	}
+
+	return &mon
+}
+
+// started notifies the observer that a round has started.
+func (mon *monitor) started(mdl Model) {
+	if mon.cfg.Observer != nil {
+		mon.cfg.Observer.RoundStarted(mon.round, mdl)
+	}
+}
+
+// assigned records the number of data points reassigned in an
+// assignment step.
+func (mon *monitor) assigned(mdl Model, reassigns int) {
+	if mon.rpt != nil {
+		mon.rpt.Reassigns = append(mon.rpt.Reassigns, reassigns)
+	}
+
+	if mon.cfg.Observer != nil {
+		mon.cfg.Observer.Assigned(mon.round, mdl, reassigns)
+	}
+}
+
+// before records the means before an update.
+func (mon *monitor) before(mdl Model) {
+	mon.prevMdl.copyFrom(mdl)
+}
+
+// after returns the criterion met by the means after an update that
+// repaired a given number of empty clusters, or zero if training
+// should continue.
+func (mon *monitor) after(mdl Model, data []Point, repairs int) StopReason {
+	mon.iters++
+
+	var (
+		shift       = mdl.shift(mon.prevMdl)
+		prevInertia = mon.inertia
+		observed    = true
+	)
+
+	if mon.rpt != nil || mon.cfg.Observer != nil || 0 < mon.cfg.InertiaTol {
+		mon.inertia = -mdl.score(mon.cfg.Metric, data)
+	}
+
+	if mon.rpt != nil {
+		mon.rpt.Iters = mon.iters
+		mon.rpt.Inertias = append(mon.rpt.Inertias, mon.inertia)
+		mon.rpt.Shifts = append(mon.rpt.Shifts, shift)
+		mon.rpt.Repairs += repairs
+	}
+
+	if mon.cfg.Observer != nil {
+		observed = mon.cfg.Observer.Updated(mon.round, mdl, mon.inertia)
+	}
+
+	switch {
+	case mon.ctx.Err() != nil:
+		return Canceled
+	case 0 < repairs && mon.cfg.RepairMthd == RepairFail:
+		return EmptyCluster
+	case !observed:
+		return Stopped
+	case 0 < mon.cfg.MaxIters && mon.cfg.MaxIters <= mon.iters:
+		return MaxIters
+	case 0 < mon.cfg.ShiftTol && shift <= mon.cfg.ShiftTol:
+		return ShiftTol
+	case 0 < mon.cfg.InertiaTol && (mon.inertia == 0 || mon.iters != 1 && (prevInertia-mon.inertia)/prevInertia < mon.cfg.InertiaTol):
+		return InertiaTol
+	default:
+		return 0
+	}
+}
+
+// batched returns the criterion met by the means after a mini-batch
+// update given the inertia of the batch, or zero if training should
+// continue.
+func (mon *monitor) batched(mdl Model, inertia float64) StopReason {
+	mon.iters++
+	shift := mdl.shift(mon.prevMdl)
+	if mon.rpt != nil {
+		mon.rpt.Iters = mon.iters
+		mon.rpt.Shifts = append(mon.rpt.Shifts, shift)
+	}
+
+	switch {
+	case mon.ctx.Err() != nil:
+		return Canceled
+	case mon.cfg.Observer != nil && !mon.cfg.Observer.Updated(mon.round, mdl, inertia):
+		return Stopped
+	case 0 < mon.cfg.BatchTol && mdl.maxDrift(mon.prevMdl) <= mon.cfg.BatchTol:
+		return ShiftTol
+	default:
+		return 0
+	}
+}
+
+// done notifies the observer that a round stopped by a given criterion
+// is done.
+func (mon *monitor) done(mdl Model, data []Point, rsn StopReason) {
+	if mon.cfg.Observer != nil {
+		mon.cfg.Observer.RoundDone(mon.round, mdl, -mdl.score(mon.cfg.Metric, data), rsn)
+	}
+}
+
+// shift returns the distance the means have moved from those of a
+// previous model relative to their magnitude. That is, the norm of the
+// drift of each mean divided by the norm of the means. If every mean
+// is the origin, the norm of the drift is returned.
+func (mdl Model) shift(prevMdl Model) float64 {
+	var sqDrift, sqMag float64
+	for i := 0; i < len(mdl); i++ {
+		sqDrift += mdl[i].SqDist(prevMdl[i])
+		sqMag += mdl[i].Dot(mdl[i])
+	}
+
+	if sqMag == 0 {
+		return math.Sqrt(sqDrift)
+	}
+
+	return math.Sqrt(sqDrift / sqMag)
+}
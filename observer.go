@@ -0,0 +1,26 @@
+package kmeans
+
+// Observer observes each training round as it progresses. Each method
+// is given the index of the round and the model being trained, which
+// must not be modified or retained without copying it.
+type Observer interface {
+	// RoundStarted is called once a model is initialized, before it
+	// is trained.
+	RoundStarted(round int, mdl Model)
+
+	// Assigned is called after each assignment step with the number of
+	// data points reassigned. This is not called by the mini-batch
+	// method.
+	Assigned(round int, mdl Model, reassigns int)
+
+	// Updated is called after each update of the means with the
+	// inertia, the sum of the cost by the configured metric of the
+	// distance from each data point to its mean. For the mini-batch
+	// method, the inertia is that of the batch before its update.
+	// Returning false stops training.
+	Updated(round int, mdl Model, inertia float64) bool
+
+	// RoundDone is called once a model is trained with its inertia and
+	// the criterion that stopped training.
+	RoundDone(round int, mdl Model, inertia float64, rsn StopReason)
+}
@@ -1,5 +1,7 @@
 package kmeans
 
+import "math/rand"
+
 // Option updates a configuration.
 type Option func(*Config)
 
@@ -12,3 +14,218 @@ func SetTrainRounds(trainRounds int) Option {
 func SetInitMethod(mthd InitMethod) Option {
 	return func(cfg *Config) { cfg.Mthd = mthd }
 }
+
+// SetTrials sets the number of candidates sampled for each mean when
+// initializing with the greedy k-means++ method. A non-positive number
+// of trials defaults to 2+ln(k).
+func SetTrials(trials int) Option {
+	return func(cfg *Config) { cfg.Trials = trials }
+}
+
+// SetInitRounds sets the number of rounds candidates are sampled in
+// when initializing with the k-means|| method.
+func SetInitRounds(initRounds int) Option {
+	return func(cfg *Config) { cfg.InitRounds = initRounds }
+}
+
+// SetOversampling sets the expected number of candidates sampled each
+// round when initializing with the k-means|| method. A non-positive
+// oversampling factor defaults to 2k.
+func SetOversampling(oversampling float64) Option {
+	return func(cfg *Config) { cfg.Oversampling = oversampling }
+}
+
+// SetTrainMethod sets the training method.
+func SetTrainMethod(mthd TrainMethod) Option {
+	return func(cfg *Config) { cfg.TrainMthd = mthd }
+}
+
+// SetBatchSize sets the number of data points sampled in each batch
+// when training with the mini-batch method.
+func SetBatchSize(batchSize int) Option {
+	return func(cfg *Config) { cfg.BatchSize = batchSize }
+}
+
+// SetBatchIters sets the maximum number of batches used when training
+// with the mini-batch method.
+func SetBatchIters(batchIters int) Option {
+	return func(cfg *Config) { cfg.BatchIters = batchIters }
+}
+
+// SetBatchTol sets the tolerance used to stop training with the
+// mini-batch method early. Training stops once no mean moves farther
+// than the tolerance after a batch. A non-positive tolerance disables
+// stopping early.
+func SetBatchTol(batchTol float64) Option {
+	return func(cfg *Config) { cfg.BatchTol = batchTol }
+}
+
+// SetMaxIters sets the maximum number of iterations each training
+// round may update the means. Zero indicates no maximum. This does not
+// apply to the mini-batch method; see SetBatchIters.
+func SetMaxIters(maxIters int) Option {
+	return func(cfg *Config) { cfg.MaxIters = maxIters }
+}
+
+// SetShiftTol sets the tolerance on how far the means may move in an
+// iteration relative to their magnitude before training stops. A
+// non-positive tolerance disables this criterion. This does not apply
+// to the mini-batch method; see SetBatchTol.
+func SetShiftTol(shiftTol float64) Option {
+	return func(cfg *Config) { cfg.ShiftTol = shiftTol }
+}
+
+// SetInertiaTol sets the minimum improvement in inertia (the sum of the
+// cost by the configured metric of the distance from each data point to
+// its mean) relative to the previous iteration before training stops. A non-positive tolerance
+// disables this criterion. This does not apply to the mini-batch
+// method.
+func SetInertiaTol(inertiaTol float64) Option {
+	return func(cfg *Config) { cfg.InertiaTol = inertiaTol }
+}
+
+// SetRand sets the source of every random decision made while
+// initializing and training. Identical sources, data, and options
+// produce identical models.
+func SetRand(rnd *rand.Rand) Option {
+	return func(cfg *Config) { cfg.Rand = rnd }
+}
+
+// SetSeed sets the source of every random decision made while
+// initializing and training to one seeded with the given value.
+// Identical seeds, data, and options produce identical models.
+func SetSeed(seed int64) Option {
+	return func(cfg *Config) { cfg.Rand = rand.New(rand.NewSource(seed)) }
+}
+
+// SetObserver sets the observer notified as each training round
+// progresses. If more than one worker is set, the observer must be
+// safe for concurrent use.
+func SetObserver(obs Observer) Option {
+	return func(cfg *Config) { cfg.Observer = obs }
+}
+
+// SetWorkers sets the number of training rounds that may be trained
+// concurrently. By default, this is GOMAXPROCS.
+func SetWorkers(workers int) Option {
+	return func(cfg *Config) { cfg.Workers = workers }
+}
+
+// SetStepWorkers sets the number of goroutines the steps of each
+// training round are shared among. These are the assignment step of
+// Lloyd's, Elkan's, and Hamerly's algorithms and the update of each
+// mean to the average of its cluster. Incremental updates, updates to
+// medians, and the mini-batch method run on a single goroutine. The
+// model returned does not depend on the number of step workers. By
+// default, there is one step worker.
+func SetStepWorkers(stepWorkers int) Option {
+	return func(cfg *Config) { cfg.StepWorkers = stepWorkers }
+}
+
+// SetIncremental sets whether the means are updated incrementally.
+// That is, the sum and size of each class are kept between updates,
+// subtracting and adding only the data points that changed class, and
+// only the means of classes that changed are updated. This suits large
+// k, but rounding error may accumulate in the sums.
+func SetIncremental(incremental bool) Option {
+	return func(cfg *Config) { cfg.Incremental = incremental }
+}
+
+// SetRepairMethod sets how the mean of a cluster is repaired when no
+// data points are assigned to it during training.
+func SetRepairMethod(mthd RepairMethod) Option {
+	return func(cfg *Config) { cfg.RepairMthd = mthd }
+}
+
+// SetMetric sets the metric measuring the distance from each data
+// point to each mean during training. The metric's cost of each
+// distance defines the inertia and the score rounds are compared by.
+// By default, this is Euclidean, whose cost is the squared distance.
+func SetMetric(metric Metric) Option {
+	return func(cfg *Config) { cfg.Metric = metric }
+}
+
+// SetSpherical sets whether training is spherical. If so, the cosine
+// metric is set, each data point is assigned to the mean maximizing
+// its dot product, and each mean is normalized after each update.
+func SetSpherical(spherical bool) Option {
+	return func(cfg *Config) {
+		cfg.Spherical = spherical
+		if spherical {
+			cfg.Metric = Cosine{}
+		}
+	}
+}
+
+// SetMedians sets whether each mean is updated to the coordinate-wise
+// median of its cluster rather than the average (k-medians). If so,
+// the Manhattan metric is set, and the inertia and score of each round
+// are the sum of the unsquared Manhattan distances k-medians minimizes.
+func SetMedians(medians bool) Option {
+	return func(cfg *Config) {
+		cfg.Medians = medians
+		if medians {
+			cfg.Metric = Manhattan{}
+		}
+	}
+}
+
+// SetMedoidMethod sets the method training a k-medoids model. By
+// default, FasterPAM is used.
+func SetMedoidMethod(mthd MedoidMethod) Option {
+	return func(cfg *Config) { cfg.MedoidMthd = mthd }
+}
+
+// SetSamples sets the number of samples CLARA trains medoids on in
+// each training round. By default, five samples are drawn.
+func SetSamples(samples int) Option {
+	return func(cfg *Config) { cfg.Samples = samples }
+}
+
+// SetSampleSize sets the number of data points in each sample CLARA
+// trains medoids on. By default, or if zero, each sample has 40+2k
+// data points.
+func SetSampleSize(sampleSize int) Option {
+	return func(cfg *Config) { cfg.SampleSize = sampleSize }
+}
+
+// SetNeighbors sets the number of random swaps CLARANS tries without
+// reducing the total distance before stopping. By default, or if zero,
+// the larger of 250 and 1.25% of k(n-k) is used.
+func SetNeighbors(neighbors int) Option {
+	return func(cfg *Config) { cfg.Neighbors = neighbors }
+}
+
+// SetBisectMethod sets which cluster bisecting k-means splits next. By
+// default, the cluster with the largest error is split.
+func SetBisectMethod(mthd BisectMethod) Option {
+	return func(cfg *Config) { cfg.BisectMthd = mthd }
+}
+
+// SetBisectTrials sets the number of times bisecting k-means trains
+// 2-means on each cluster it splits, keeping the highest scoring split.
+// This replaces the number of training rounds for each split. By
+// default, there are five trials.
+func SetBisectTrials(trials int) Option {
+	return func(cfg *Config) { cfg.BisectTrials = trials }
+}
+
+// SetSignificance sets the significance level G-means tests whether
+// each cluster is Gaussian at. A cluster is split if the test rejects
+// it at this level. By default, the level is 0.0001.
+func SetSignificance(significance float64) Option {
+	return func(cfg *Config) { cfg.Significance = significance }
+}
+
+// SetReferences sets the number of reference data sets the gap
+// statistic is computed against. By default, ten are sampled.
+func SetReferences(references int) Option {
+	return func(cfg *Config) { cfg.References = references }
+}
+
+// SetPCABox sets whether reference data sets for the gap statistic
+// are sampled uniformly over the box aligned with the principal
+// components of the data rather than its bounding box.
+func SetPCABox(pcaBox bool) Option {
+	return func(cfg *Config) { cfg.PCABox = pcaBox }
+}
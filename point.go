@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"encoding/csv"
 	"encoding/json"
-	"errors"
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -18,7 +18,7 @@ type Point []float64
 // Add a point q to p. That is, p += q.
 func (p Point) Add(q Point) {
 	if len(p) != len(q) {
-		panic(errDims)
+		panic(ErrDims)
 	}
 
 	for i := 0; i < len(p); i++ {
@@ -39,7 +39,7 @@ func Add(ps ...Point) Point {
 // Compare returns -1 if p < q, 1 if p > q, or 0 if p = q.
 func (p Point) Compare(q Point) int {
 	if len(p) != len(q) {
-		panic(errDims)
+		panic(ErrDims)
 	}
 
 	for i := 0; i < len(p); i++ {
@@ -77,6 +77,18 @@ func (p Point) Near(q Point, tol float64) bool {
 	return true
 }
 
+// finite determines if each value of a point is neither NaN nor
+// infinite.
+func (p Point) finite() bool {
+	for i := 0; i < len(p); i++ {
+		if math.IsNaN(p[i]) || math.IsInf(p[i], 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Dims returns the dimensions of the set of points.
 func Dims(ps ...Point) int {
 	if err := Validate(ps...); err != nil {
@@ -86,6 +98,21 @@ func Dims(ps ...Point) int {
 	return len(ps[0])
 }
 
+// distinct returns the number of distinct points in a set of points.
+func distinct(ps []Point) int {
+	sorted := append(make([]Point, 0, len(ps)), ps...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Compare(sorted[j]) < 0 })
+
+	var n int
+	for i := 0; i < len(sorted); i++ {
+		if i == 0 || !sorted[i].Equals(sorted[i-1]) {
+			n++
+		}
+	}
+
+	return n
+}
+
 // Dist returns the Euclidean distance between two points.
 func (p Point) Dist(q Point) float64 {
 	return math.Sqrt(p.SqDist(q))
@@ -191,6 +218,17 @@ func ReadJSONFile(file string) ([]Point, error) {
 	return ParseJSON(string(b))
 }
 
+// Sub subtracts a point q from p. That is, p -= q.
+func (p Point) Sub(q Point) {
+	if len(p) != len(q) {
+		panic(ErrDims)
+	}
+
+	for i := 0; i < len(p); i++ {
+		p[i] -= q[i]
+	}
+}
+
 // ScalMult mulitplies a point p by a. That is, p *= a.
 func (p Point) ScalMult(a float64) {
 	for i := 0; i < len(p); i++ {
@@ -239,7 +277,7 @@ func Norms(ps ...Point) []Point {
 // SqDist returns the squared Euclidean distance between two points.
 func (p Point) SqDist(q Point) float64 {
 	if len(p) != len(q) {
-		panic(errDims)
+		panic(ErrDims)
 	}
 
 	var sd float64 // sd = sum((pi-qi)^2)
@@ -271,7 +309,7 @@ func (p Point) String() string {
 func Validate(data ...Point) error {
 	for i := 1; i < len(data); i++ {
 		if len(data[0]) != len(data[i]) {
-			return errors.New(errDims)
+			return ErrDims
 		}
 	}
 
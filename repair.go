@@ -0,0 +1,91 @@
+package kmeans
+
+// repair the means of the given empty classes by the configured repair
+// method, returning the number of empty classes. Classes repaired by
+// splitting another class or taking the data point farthest from its
+// mean never take the same data point twice.
+func (mdl Model) repair(cfg Config, empties []int, cls classes, data []Point) int {
+	if len(empties) == 0 {
+		return 0
+	}
+
+	switch cfg.RepairMthd {
+	case RepairRandom:
+		for _, i := range empties {
+			copy(mdl[i], data[cfg.Rand.Intn(len(data))])
+		}
+	case RepairFarthest, RepairSplitLargest, RepairSplitMaxErr:
+		var (
+			dists = make([]float64, len(data))
+			sizes = make([]float64, len(mdl))
+			errs  = make([]float64, len(mdl))
+		)
+
+		for j := 0; j < len(data); j++ {
+			dists[j] = cfg.Metric.Dist(mdl[cls[j]], data[j])
+			sizes[cls[j]]++
+			errs[cls[j]] += cfg.Metric.Cost(dists[j])
+		}
+
+		for _, i := range empties {
+			var weights []float64
+			switch cfg.RepairMthd {
+			case RepairSplitLargest:
+				weights = sizes
+			case RepairSplitMaxErr:
+				weights = errs
+			}
+
+			split := -1
+			if weights != nil {
+				for c := 0; c < len(weights); c++ {
+					if split < 0 || weights[split] < weights[c] {
+						split = c
+					}
+				}
+			}
+
+			far := -1
+			for j := 0; j < len(data); j++ {
+				if (split < 0 || cls[j] == split) && 0 <= dists[j] && (far < 0 || dists[far] < dists[j]) {
+					far = j
+				}
+			}
+
+			if far < 0 {
+				// Every data point has been taken
+				continue
+			}
+
+			// Splitting roughly halves the size and error of a class
+			copy(mdl[i], data[far])
+			dists[far] = -1
+			if 0 <= split {
+				sizes[split] /= 2
+				errs[split] /= 2
+			}
+		}
+	case RepairKeep, RepairDrop, RepairFail:
+	default:
+		panic(ErrRepairMthd)
+	}
+
+	return len(empties)
+}
+
+// dropEmpty returns the means of non-empty clusters of a given data
+// set classified by a given metric.
+func (mdl Model) dropEmpty(metric Metric, data []Point) Model {
+	var (
+		sizes = mdl.SizesBy(metric, data...)
+		cpy   = make(Model, 0, len(mdl))
+	)
+
+	for i := 0; i < len(mdl); i++ {
+		if sizes[i] != 0 {
+			cpy = append(cpy, mdl[i].Copy())
+		}
+	}
+
+	return cpy
+}
@@ -0,0 +1,65 @@
+package kmeans
+
+// RepairMethod defines how the mean of a cluster is repaired when no
+// data points are assigned to it during training.
+type RepairMethod uint
+
+const (
+	// RepairRandom indicates an empty cluster will be given a random
+	// data point as its mean.
+	RepairRandom RepairMethod = 1 + iota
+
+	// RepairFarthest indicates an empty cluster will be given the data
+	// point farthest from its mean as its mean.
+	RepairFarthest
+
+	// RepairSplitLargest indicates an empty cluster will split the
+	// largest cluster, taking the data point in it farthest from its
+	// mean as its mean.
+	RepairSplitLargest
+
+	// RepairSplitMaxErr indicates an empty cluster will split the
+	// cluster with the largest error, taking the data point in it
+	// farthest from its mean as its mean.
+	RepairSplitMaxErr
+
+	// RepairKeep indicates an empty cluster will keep its previous
+	// mean.
+	RepairKeep
+
+	// RepairDrop indicates an empty cluster will keep its previous
+	// mean during training, but will be dropped from the trained model
+	// if it is still empty. The model may have fewer than k means.
+	RepairDrop
+
+	// RepairFail indicates training will stop with an error once a
+	// cluster is empty.
+	RepairFail
+)
+
+// String describes a repair method.
+func (mthd RepairMethod) String() string {
+	switch mthd {
+	case RepairRandom:
+		return "random"
+	case RepairFarthest:
+		return "farthest"
+	case RepairSplitLargest:
+		return "split-largest"
+	case RepairSplitMaxErr:
+		return "split-max-err"
+	case RepairKeep:
+		return "keep"
+	case RepairDrop:
+		return "drop"
+	case RepairFail:
+		return "fail"
+	default:
+		return "invalid"
+	}
+}
+
+// valid determines if a repair method is defined.
+func (mthd RepairMethod) valid() bool {
+	return RepairRandom <= mthd && mthd <= RepairFail
+}
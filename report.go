@@ -0,0 +1,50 @@
+package kmeans
+
+import "time"
+
+// TrainReport describes how a model was trained.
+type TrainReport struct {
+	// Rounds describes each training round in the order they were
+	// trained.
+	Rounds []RoundReport
+
+	// Best is the index of the highest scoring round, which produced
+	// the returned model.
+	Best int
+}
+
+// RoundReport describes a single training round.
+type RoundReport struct {
+	InitMthd   InitMethod
+	TrainMthd  TrainMethod
+	RepairMthd RepairMethod
+
+	// Seed seeded the random source of every random decision made in
+	// the round.
+	Seed int64
+
+	// Iters is the number of times the means were updated.
+	Iters int
+
+	// Inertias holds the sum of the cost by the configured metric of
+	// the distance from each data point to its mean after each update.
+	// This is not recorded by the mini-batch method.
+	Inertias []float64
+
+	// Shifts holds the distance the means moved relative to their
+	// magnitude in each update.
+	Shifts []float64
+
+	// Reassigns holds the number of data points reassigned in each
+	// assignment step, beginning with the initial assignment. This is
+	// not recorded by the mini-batch method.
+	Reassigns []int
+
+	// Repairs is the number of times a cluster was empty after an
+	// update and was repaired by the repair method.
+	Repairs int
+
+	Stop     StopReason
+	Score    float64
+	Duration time.Duration
+}
@@ -0,0 +1,81 @@
+package kmeans
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// roundWorker trains rounds on a single goroutine, reusing its buffers
+// between rounds and keeping the highest scoring model it has trained.
+type roundWorker struct {
+	meanDists triMatrix
+	mdl       Model
+	maxScrMdl Model
+	cls       classes
+	maxScr    float64
+	maxRound  int
+}
+
+// newRoundWorker returns a round worker ready to train models with k
+// means on data with a given number of dimensions and data points.
+func newRoundWorker(k, dims, n int) *roundWorker {
+	w := roundWorker{
+		meanDists: newTriMatrix(k),
+		mdl:       make(Model, 0, k),
+		maxScrMdl: make(Model, 0, k),
+		cls:       make(classes, n),
+		maxScr:    -math.MaxFloat64,
+		maxRound:  -1,
+	}
+
+	for i := 0; i < k; i++ {
+		w.mdl = append(w.mdl, make(Point, dims))
+		w.maxScrMdl = append(w.maxScrMdl, make(Point, dims))
+	}
+
+	return &w
+}
+
+// train initializes and trains a model for a given round, drawing from
+// a random source seeded by the given seed, and returns a report
+// describing the round. Each iteration is recorded in the report only
+// if record is set, since measuring the inertia of each iteration
+// costs a distance calculation from every data point to every mean.
+// The model is kept if it scores higher than any model previously
+// trained by the worker, or scores the same and was trained in an
+// earlier round.
+func (w *roundWorker) train(ctx context.Context, cfg Config, round int, seed int64, data []Point, record bool) RoundReport {
+	var (
+		start = time.Now()
+		rpt   = RoundReport{
+			InitMthd:   cfg.Mthd,
+			TrainMthd:  cfg.TrainMthd,
+			RepairMthd: cfg.RepairMthd,
+			Seed:       seed,
+		}
+	)
+
+	cfg.Rand = rand.New(rand.NewSource(seed))
+	for i := 0; i < len(w.cls); i++ {
+		w.cls[i] = 0
+	}
+
+	var iterRpt *RoundReport
+	if record {
+		iterRpt = &rpt
+	}
+
+	w.mdl.init(cfg, w.meanDists, data)
+	rpt.Stop = w.mdl.train(newMonitor(ctx, cfg, round, w.mdl, iterRpt), w.meanDists, w.cls, data)
+	rpt.Score = w.mdl.score(cfg.Metric, data)
+	rpt.Duration = time.Since(start)
+	if w.maxScr < rpt.Score || w.maxScr == rpt.Score && round < w.maxRound {
+		w.maxScrMdl.copyFrom(w.mdl)
+		w.maxScr = rpt.Score
+		w.maxRound = round
+	}
+
+	return rpt
+}
@@ -0,0 +1,117 @@
+package kmeans
+
+// --------------------------------------------------------------------
+//    k-Means|| (Bahmani et al., 2012)
+// --------------------------------------------------------------------
+// 1. Choose a random data point as the first candidate and compute the
+//    sum ψ of squared distances from each data point to it.
+// 2. For a few rounds, sample each data point x independently with
+//    probability min(1, ℓD²(x)/ψ), where ℓ is the oversampling factor
+//    and D²(x) is the squared distance from x to the nearest
+//    candidate, then update ψ.
+// 3. Weight each candidate by the number of data points nearest to it.
+// 4. Recluster the weighted candidates into k means by D² sampling
+//    followed by Lloyd's algorithm.
+// --------------------------------------------------------------------
+// Each round samples about ℓ candidates in a single pass over the
+// data, rather than one candidate per pass as in k-means++.
+// --------------------------------------------------------------------
+//  * Scalable k-means++.
+//    https://theory.stanford.edu/~sergei/papers/vldb12-kmpar.pdf
+// --------------------------------------------------------------------
+
+// initScalable initializes a model by the k-means|| method, sampling
+// candidates for the configured number of rounds with the given
+// oversampling factor. The mean distances are updated.
+func (mdl Model) initScalable(cfg Config, oversampling float64, meanDists triMatrix, data []Point) {
+	var (
+		cands      = []Point{data[cfg.Rand.Intn(len(data))]}
+		nearest    = make([]int, len(data))
+		minSqDists = make([]float64, len(data))
+		pot        float64
+	)
+
+	for j := 0; j < len(data); j++ {
+		minSqDists[j] = sqDist(cfg.Metric, cands[0], data[j])
+		pot += minSqDists[j]
+	}
+
+	for r := 0; r < cfg.InitRounds && 0 < pot; r++ {
+		first := len(cands)
+		for j := 0; j < len(data); j++ {
+			if cfg.Rand.Float64()*pot < oversampling*minSqDists[j] {
+				cands = append(cands, data[j])
+			}
+		}
+
+		pot = 0
+		for j := 0; j < len(data); j++ {
+			for c := first; c < len(cands); c++ {
+				if sqDist := sqDist(cfg.Metric, cands[c], data[j]); sqDist < minSqDists[j] {
+					minSqDists[j] = sqDist
+					nearest[j] = c
+				}
+			}
+
+			pot += minSqDists[j]
+		}
+	}
+
+	if len(cands) <= len(mdl) {
+		// Too few candidates to recluster; fill in with random data
+		// points
+		mdl[:len(cands)].copyFrom(cands)
+		for i := len(cands); i < len(mdl); i++ {
+			copy(mdl[i], data[cfg.Rand.Intn(len(data))])
+		}
+
+		meanDists.update(cfg.Metric, mdl)
+		return
+	}
+
+	weights := make([]float64, len(cands))
+	for j := 0; j < len(data); j++ {
+		weights[nearest[j]]++
+	}
+
+	mdl.initD2(cfg.Rand, cfg.Metric, 1, meanDists, cands, weights)
+	mdl.trainWeighted(cfg, meanDists, cands, weights)
+}
+
+// trainWeighted updates the means using the given weighted data set
+// and mean distance lookup table by Lloyd's algorithm, where each data
+// point counts as many times as its weight. If configured for
+// k-medians, each mean is updated to the weighted median of its class.
+func (mdl Model) trainWeighted(cfg Config, meanDists triMatrix, data []Point, weights []float64) {
+	cls := make(classes, len(data))
+	for 0 < cls.update(cfg, mdl, meanDists, data) {
+		if cfg.Medians {
+			// Empty clusters keep their previous median
+			mdl.medians(cls, data, weights)
+			meanDists.update(cfg.Metric, mdl)
+			continue
+		}
+
+		for i := 0; i < len(mdl); i++ {
+			var (
+				mean = make(Point, len(mdl[i]))
+				size float64
+			)
+
+			for j := 0; j < len(data); j++ {
+				if i == cls[j] {
+					mean.Add(ScalMult(data[j], weights[j]))
+					size += weights[j]
+				}
+			}
+
+			if size != 0 {
+				// Empty clusters keep their previous mean
+				mean.ScalMult(1.0 / size)
+				copy(mdl[i], mean)
+			}
+		}
+
+		meanDists.update(cfg.Metric, mdl)
+	}
+}
@@ -0,0 +1,57 @@
+package kmeans
+
+// --------------------------------------------------------------------
+//    Spherical k-means (Dhillon and Modha, 2001)
+// --------------------------------------------------------------------
+// 1. Normalize each mean to unit length.
+// 2. Assign each point x to the mean c maximizing the dot product
+//    x·c, which is the mean with the greatest cosine similarity to x.
+// 3. Update each mean to the average of the points assigned to it and
+//    normalize it to unit length.
+// 4. Return when no reassignments are made, otherwise go to step 2.
+// --------------------------------------------------------------------
+//  * Concept decompositions for large sparse text data using
+//    clustering.
+//    https://doi.org/10.1023/A:1007612920971
+// --------------------------------------------------------------------
+
+// Sim returns the cosine similarity between a class mean and a given
+// point. For a spherically trained model, this is the dot product of
+// the mean and the normalized point.
+func (mdl Model) Sim(class int, datum Point) float64 {
+	return 1.0 - Cosine{}.Dist(mdl[class], datum)
+}
+
+// ClassSim returns the classification of a point and its cosine
+// similarity to its mean. That is, the class whose mean has the
+// greatest cosine similarity to the point.
+func (mdl Model) ClassSim(datum Point) (int, float64) {
+	class, dist := mdl.classDist(Cosine{}, datum)
+	return class, 1.0 - dist
+}
+
+// ClassesSim returns the classification of each data point by cosine
+// similarity.
+func (mdl Model) ClassesSim(data ...Point) []int {
+	classes := make([]int, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		class, _ := mdl.classDist(Cosine{}, data[i])
+		classes = append(classes, class)
+	}
+
+	return classes
+}
+
+// ScoreSim indicates how well a spherically trained model clusters
+// data. That is, the sum of the cosine similarity between each data
+// point and its mean. A higher score indicates the model is a better
+// fit.
+func (mdl Model) ScoreSim(data ...Point) float64 {
+	var sumSims float64
+	for i := 0; i < len(data); i++ {
+		_, dist := mdl.classDist(Cosine{}, data[i])
+		sumSims += 1.0 - dist
+	}
+
+	return sumSims
+}
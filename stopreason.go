@@ -0,0 +1,57 @@
+package kmeans
+
+// StopReason defines the criterion that stopped training.
+type StopReason uint
+
+const (
+	// Converged indicates training stopped because no data points were
+	// reassigned.
+	Converged StopReason = 1 + iota
+
+	// MaxIters indicates training stopped because the maximum number
+	// of iterations was reached.
+	MaxIters
+
+	// ShiftTol indicates training stopped because the means moved less
+	// than the shift tolerance relative to their magnitudes.
+	ShiftTol
+
+	// InertiaTol indicates training stopped because the inertia
+	// improved less than the inertia tolerance relative to the
+	// previous inertia.
+	InertiaTol
+
+	// Canceled indicates training stopped because its context was
+	// canceled.
+	Canceled
+
+	// Stopped indicates training stopped because an observer requested
+	// it.
+	Stopped
+
+	// EmptyCluster indicates training stopped because a cluster became
+	// empty and the repair method is RepairFail.
+	EmptyCluster
+)
+
+// String describes a stop reason.
+func (rsn StopReason) String() string {
+	switch rsn {
+	case Converged:
+		return "converged"
+	case MaxIters:
+		return "maximum iterations"
+	case ShiftTol:
+		return "shift tolerance"
+	case InertiaTol:
+		return "inertia tolerance"
+	case Canceled:
+		return "canceled"
+	case Stopped:
+		return "stopped"
+	case EmptyCluster:
+		return "empty cluster"
+	default:
+		return "invalid"
+	}
+}
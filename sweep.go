@@ -0,0 +1,283 @@
+package kmeans
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// --------------------------------------------------------------------
+//    Kneedle (Satopää et al., 2011)
+// --------------------------------------------------------------------
+// 1. Normalize k and the inertia of each model to [0, 1].
+// 2. Flip the normalized inertia so the curve increases. That is,
+//    y' = 1 - y.
+// 3. Compute the difference y' - x at each k.
+// 4. Return the k maximizing the difference, which is the point of the
+//    curve farthest above the line joining its ends.
+// --------------------------------------------------------------------
+//  * Finding a "kneedle" in a haystack: detecting knee points in
+//    system behavior.
+//    https://raghavan.usc.edu/papers/kneedle-simplex11.pdf
+// --------------------------------------------------------------------
+
+// SweepStep describes a model trained with k means. The inertia is the
+// sum of squared distances from each data point to its mean. A higher
+// silhouette and Calinski-Harabasz index, and a lower Davies-Bouldin
+// index, indicate better separated clusters. Each index is NaN if k is
+// one.
+type SweepStep struct {
+	K                int
+	Model            Model
+	Inertia          float64
+	Silhouette       float64
+	CalinskiHarabasz float64
+	DaviesBouldin    float64
+}
+
+// SweepReport describes each model trained over a range of k and the
+// k suggested by the elbow of the inertia.
+type SweepReport struct {
+	Steps []SweepStep
+	Elbow int
+}
+
+// Sweep trains a model for each k from kMin to kMax with the given
+// options and returns a report describing each. Models are trained
+// concurrently by the configured number of workers, each drawing from
+// its own random source seeded by the configured source. Computing the
+// silhouette grows with the square of the size of the data set.
+func Sweep(kMin, kMax int, data []Point, opts ...Option) (SweepReport, error) {
+	return SweepContext(context.Background(), kMin, kMax, data, opts...)
+}
+
+// SweepContext returns a report as Sweep does, but stops training once
+// the context is canceled, returning the context's error.
+func SweepContext(ctx context.Context, kMin, kMax int, data []Point, opts ...Option) (SweepReport, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.Validate(); err != nil {
+		return SweepReport{}, err
+	}
+
+	if kMax < kMin {
+		return SweepReport{}, fmt.Errorf("%w: %d to %d", ErrK, kMin, kMax)
+	}
+
+	if err := validate(kMin, data); err != nil {
+		return SweepReport{}, err
+	}
+
+	if err := validate(kMax, data); err != nil {
+		return SweepReport{}, err
+	}
+
+	var (
+		n     = kMax - kMin + 1
+		seeds = make([]int64, n)
+		steps = make([]SweepStep, n)
+		errs  = make([]error, n)
+		jobs  = make(chan int)
+		wg    sync.WaitGroup
+	)
+
+	for i := 0; i < len(seeds); i++ {
+		seeds[i] = cfg.Rand.Int63()
+	}
+
+	for w := 0; w < cfg.Workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				stepCfg := cfg
+				stepCfg.Rand = rand.New(rand.NewSource(seeds[i]))
+				mdl, _, err := newModel(ctx, kMin+i, data, stepCfg, false)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+
+				steps[i] = mdl.sweepStep(data)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	for i := 0; i < len(errs); i++ {
+		if errs[i] != nil {
+			return SweepReport{}, errs[i]
+		}
+	}
+
+	return SweepReport{Steps: steps, Elbow: elbow(steps)}, nil
+}
+
+// sweepStep returns a description of a model on a given data set.
+func (mdl Model) sweepStep(data []Point) SweepStep {
+	var (
+		classes  = mdl.Classes(data...)
+		clusters = make([][]Point, len(mdl))
+		step     = SweepStep{
+			K:                len(mdl),
+			Model:            mdl,
+			Inertia:          -mdl.Score(data...),
+			Silhouette:       math.NaN(),
+			CalinskiHarabasz: math.NaN(),
+			DaviesBouldin:    math.NaN(),
+		}
+	)
+
+	if len(mdl) < 2 {
+		return step
+	}
+
+	for i := 0; i < len(data); i++ {
+		clusters[classes[i]] = append(clusters[classes[i]], data[i])
+	}
+
+	step.Silhouette = silhouette(classes, clusters, data)
+	step.CalinskiHarabasz = mdl.calinskiHarabasz(clusters, data, step.Inertia)
+	step.DaviesBouldin = mdl.daviesBouldin(clusters)
+	return step
+}
+
+// silhouette returns the mean silhouette of each data point, given its
+// classification and each cluster. That is, (b-a)/max(a,b), where a is
+// the mean distance from a data point to the rest of its cluster and b
+// is the least mean distance to the points of another cluster. Data
+// points alone in their cluster have a silhouette of zero.
+func silhouette(classes []int, clusters [][]Point, data []Point) float64 {
+	var total float64
+	for i := 0; i < len(data); i++ {
+		class := classes[i]
+		if len(clusters[class]) < 2 {
+			continue
+		}
+
+		var (
+			a = meanDist(data[i], clusters[class]) * float64(len(clusters[class])) / float64(len(clusters[class])-1)
+			b = math.Inf(1)
+		)
+
+		for c := 0; c < len(clusters); c++ {
+			if c != class && len(clusters[c]) != 0 {
+				b = math.Min(b, meanDist(data[i], clusters[c]))
+			}
+		}
+
+		if r := math.Max(a, b); r != 0 && !math.IsInf(b, 1) {
+			total += (b - a) / r
+		}
+	}
+
+	return total / float64(len(data))
+}
+
+// meanDist returns the mean distance from a point to each point in a
+// cluster.
+func meanDist(p Point, cluster []Point) float64 {
+	var total float64
+	for i := 0; i < len(cluster); i++ {
+		total += p.Dist(cluster[i])
+	}
+
+	return total / float64(len(cluster))
+}
+
+// calinskiHarabasz returns the ratio of the dispersion between
+// clusters to the dispersion within clusters, each divided by its
+// degrees of freedom, given the inertia of the model.
+func (mdl Model) calinskiHarabasz(clusters [][]Point, data []Point, inertia float64) float64 {
+	center := Add(data...)
+	center.ScalMult(1.0 / float64(len(data)))
+
+	var between float64
+	for i := 0; i < len(mdl); i++ {
+		between += float64(len(clusters[i])) * mdl[i].SqDist(center)
+	}
+
+	if inertia == 0 {
+		return math.Inf(1)
+	}
+
+	return between / float64(len(mdl)-1) / (inertia / float64(len(data)-len(mdl)))
+}
+
+// daviesBouldin returns the mean, over each cluster, of the greatest
+// ratio of the sum of the scatter of the cluster and another cluster
+// to the distance between their means. The scatter of a cluster is the
+// mean distance from each of its data points to its mean. Empty
+// clusters are ignored.
+func (mdl Model) daviesBouldin(clusters [][]Point) float64 {
+	scatters := make([]float64, len(mdl))
+	for i := 0; i < len(mdl); i++ {
+		if len(clusters[i]) != 0 {
+			scatters[i] = meanDist(mdl[i], clusters[i])
+		}
+	}
+
+	var (
+		total float64
+		k     int
+	)
+
+	for i := 0; i < len(mdl); i++ {
+		if len(clusters[i]) == 0 {
+			continue
+		}
+
+		var maxRatio float64
+		for j := 0; j < len(mdl); j++ {
+			if j != i && len(clusters[j]) != 0 {
+				maxRatio = math.Max(maxRatio, (scatters[i]+scatters[j])/mdl[i].Dist(mdl[j]))
+			}
+		}
+
+		total += maxRatio
+		k++
+	}
+
+	return total / float64(k)
+}
+
+// elbow returns the k at the elbow of the inertia of each step by the
+// kneedle algorithm. If there are fewer than three steps, or the
+// inertia never falls below the line joining its ends, the first k is
+// returned.
+func elbow(steps []SweepStep) int {
+	if len(steps) < 3 {
+		return steps[0].K
+	}
+
+	var (
+		first, last = steps[0], steps[len(steps)-1]
+		maxK        = first.K
+		maxDiff     float64
+	)
+
+	if first.Inertia == last.Inertia {
+		return maxK
+	}
+
+	for i := 1; i < len(steps)-1; i++ {
+		var (
+			x = float64(steps[i].K-first.K) / float64(last.K-first.K)
+			y = (steps[i].Inertia - last.Inertia) / (first.Inertia - last.Inertia)
+		)
+
+		if diff := (1 - y) - x; maxDiff < diff {
+			maxK = steps[i].K
+			maxDiff = diff
+		}
+	}
+
+	return maxK
+}
@@ -0,0 +1,46 @@
+package kmeans
+
+// TrainMethod defines how a model is trained after initialization.
+type TrainMethod uint
+
+const (
+	// Lloyd indicates a model will be trained with Lloyd's algorithm,
+	// reassigning every point to its nearest mean each iteration.
+	Lloyd TrainMethod = 1 + iota
+
+	// Elkan indicates a model will be trained with Elkan's algorithm,
+	// maintaining per-point distance bounds to skip most distance
+	// calculations. The means are the same as those from Lloyd.
+	Elkan
+
+	// Hamerly indicates a model will be trained with Hamerly's
+	// algorithm, maintaining one upper and one lower distance bound
+	// per point. The means are the same as those from Lloyd.
+	Hamerly
+
+	// MiniBatch indicates a model will be trained on small batches
+	// sampled from the data rather than the full data set each
+	// iteration. The means approximate those from Lloyd.
+	MiniBatch
+)
+
+// String describes a training method.
+func (mthd TrainMethod) String() string {
+	switch mthd {
+	case Lloyd:
+		return "lloyd"
+	case Elkan:
+		return "elkan"
+	case Hamerly:
+		return "hamerly"
+	case MiniBatch:
+		return "mini-batch"
+	default:
+		return "invalid"
+	}
+}
+
+// valid determines if a training method is defined.
+func (mthd TrainMethod) valid() bool {
+	return Lloyd <= mthd && mthd <= MiniBatch
+}
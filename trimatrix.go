@@ -31,12 +31,13 @@ func (mtx triMatrix) dist(i, j int) float64 {
 	}
 }
 
-// update a triangular matrix. Assumes the size of the data is the
-// same as when the triangular matrix was initialized.
-func (mtx triMatrix) update(data []Point) {
+// update a triangular matrix with the distances by a given metric.
+// Assumes the size of the data is the same as when the triangular
+// matrix was initialized.
+func (mtx triMatrix) update(metric Metric, data []Point) {
 	for i, k := 0, 0; i < len(data); i++ {
 		for j := 0; j < i; j++ {
-			mtx[k] = data[i].Dist(data[j])
+			mtx[k] = metric.Dist(data[i], data[j])
 			k++
 		}
 	}
@@ -0,0 +1,195 @@
+package kmeans
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// --------------------------------------------------------------------
+//    X-means (Pelleg and Moore, 2000)
+// --------------------------------------------------------------------
+// 1. Train a model with the fewest number of means allowed.
+// 2. Split each cluster in two by 2-means and compute the Bayesian
+//    information criterion (BIC) of the cluster as a single spherical
+//    Gaussian and as two.
+// 3. Keep the splits that improve the BIC, most improved first, while
+//    there are at most the greatest number of means allowed.
+// 4. Train the model of the kept means on the full data set and
+//    compute its BIC.
+// 5. Return the model with the greatest BIC once no split is kept or
+//    there are the greatest number of means allowed, otherwise go to
+//    step 2.
+// --------------------------------------------------------------------
+//  * X-means: extending k-means with efficient estimation of the
+//    number of clusters.
+//    https://www.cs.cmu.edu/~dpelleg/download/xmeans.pdf
+// --------------------------------------------------------------------
+
+// XStep is a model trained by X-means, described by its number of
+// means and its BIC.
+type XStep struct {
+	K   int
+	BIC float64
+}
+
+// XMeans returns the model trained by X-means with between kMin and
+// kMax means that has the greatest Bayesian information criterion
+// (BIC), along with the number of means and BIC of each model trained.
+// Each model and split is trained with the configured options, keeping
+// the highest scoring of the configured number of training rounds.
+func XMeans(kMin, kMax int, data []Point, opts ...Option) (Model, []XStep, error) {
+	return XMeansContext(context.Background(), kMin, kMax, data, opts...)
+}
+
+// XMeansContext returns a model and BIC trace as XMeans does, but
+// stops once the context is canceled. If canceled, the model with the
+// greatest BIC so far is returned with the context's error.
+func XMeansContext(ctx context.Context, kMin, kMax int, data []Point, opts ...Option) (Model, []XStep, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if kMax < kMin {
+		return nil, nil, fmt.Errorf("%w: %d to %d", ErrK, kMin, kMax)
+	}
+
+	if err := validate(kMin, data); err != nil {
+		return nil, nil, err
+	}
+
+	if err := validate(kMax, data); err != nil {
+		return nil, nil, err
+	}
+
+	mdl, _, err := newModel(ctx, kMin, data, cfg, false)
+	if err != nil {
+		return mdl, nil, err
+	}
+
+	var (
+		maxBIC = mdl.bic(data)
+		maxMdl = mdl.Copy()
+		trace  = []XStep{{K: len(mdl), BIC: maxBIC}}
+	)
+
+	for len(mdl) < kMax {
+		if err := ctx.Err(); err != nil {
+			return maxMdl, trace, err
+		}
+
+		type split struct {
+			class    int
+			children Model
+			gain     float64
+		}
+
+		var (
+			clusters = mdl.clusters(cfg.Metric, data)
+			splits   []split
+		)
+
+		for i := 0; i < len(clusters); i++ {
+			if len(clusters[i]) < 2 {
+				continue
+			}
+
+			children, _, err := newModel(ctx, 2, clusters[i], cfg, false)
+			if err != nil {
+				return maxMdl, trace, err
+			}
+
+			if len(children) < 2 {
+				continue
+			}
+
+			if gain := children.bic(clusters[i]) - mdl[i:i+1].bic(clusters[i]); 0 < gain {
+				splits = append(splits, split{class: i, children: children, gain: gain})
+			}
+		}
+
+		if len(splits) == 0 {
+			break
+		}
+
+		sort.SliceStable(splits, func(i, j int) bool { return splits[j].gain < splits[i].gain })
+		if kMax-len(mdl) < len(splits) {
+			splits = splits[:kMax-len(mdl)]
+		}
+
+		next := mdl.Copy()
+		for _, s := range splits {
+			copy(next[s.class], s.children[0])
+			next = append(next, s.children[1])
+		}
+
+		var (
+			meanDists = newTriMatrix(len(next))
+			cls       = make(classes, len(data))
+		)
+
+		meanDists.update(cfg.Metric, next)
+		if next.train(newMonitor(ctx, cfg, 0, next, nil), meanDists, cls, data) == Canceled {
+			return maxMdl, trace, ctx.Err()
+		}
+
+		mdl = next
+		trace = append(trace, XStep{K: len(mdl), BIC: mdl.bic(data)})
+		if bic := trace[len(trace)-1].BIC; maxBIC < bic {
+			maxBIC = bic
+			maxMdl = mdl.Copy()
+		}
+	}
+
+	return maxMdl, trace, nil
+}
+
+// clusters returns the data classified into k clusters by a given
+// metric.
+func (mdl Model) clusters(metric Metric, data []Point) [][]Point {
+	clusters := make([][]Point, len(mdl))
+	for i := 0; i < len(data); i++ {
+		class, _ := mdl.classDist(metric, data[i])
+		clusters[class] = append(clusters[class], data[i])
+	}
+
+	return clusters
+}
+
+// bic returns the Bayesian information criterion of a model on a given
+// data set, treating each cluster as a spherical Gaussian sharing the
+// same variance in every dimension. A greater BIC indicates the model
+// is a better fit. If there are no more data points than means, -Inf
+// is returned. If every data point is its mean, +Inf is returned.
+func (mdl Model) bic(data []Point) float64 {
+	var (
+		r = float64(len(data))
+		k = float64(len(mdl))
+		m = float64(len(data[0]))
+	)
+
+	if r <= k {
+		return math.Inf(-1)
+	}
+
+	variance := sum(mdl.Errs(data...)) / (m * (r - k))
+	if variance == 0 {
+		return math.Inf(1)
+	}
+
+	var (
+		sizes  = mdl.Sizes(data...)
+		params = (k - 1) + m*k + 1
+		logL   = -r/2*math.Log(2*math.Pi) - r*m/2*math.Log(variance) - m*(r-k)/2 - r*math.Log(r)
+	)
+
+	for i := 0; i < len(sizes); i++ {
+		if s := float64(sizes[i]); 0 < s {
+			logL += s * math.Log(s)
+		}
+	}
+
+	return logL - params/2*math.Log(r)
+}